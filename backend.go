@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-delve/delve/service/rpc2"
+	log "github.com/sirupsen/logrus"
+)
+
+// Prefix of the line dlv prints once its rpc server is listening.
+const listeningMsg = "API server listening at: "
+
+// Number of lines of dlv stderr shown when the backend fails.
+const stderrLines = 20
+
+// How long dlv is given to kill the debugged program before it's killed itself.
+const killTimeout = 5 * time.Second
+
+// Supervises the dlv process and the rpc connection to it.
+type Backend struct {
+	args []string // Arguments of dlv. Empty if connecting to a server not started by the client.
+	env  []string
+	addr string
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	exited chan struct{} // Closed when the current dlv process exits.
+	stderr []string      // Last lines written to stderr by dlv.
+	gen    int           // Incremented whenever the process or connection is replaced on purpose.
+
+	events chan string // Messages describing a lost backend.
+}
+
+func NewBackend(args []string, env []string) *Backend {
+	return &Backend{
+		args:   args,
+		env:    env,
+		events: make(chan string, 16),
+	}
+}
+
+// Use a dlv server that was started by someone else.
+func NewRemoteBackend(addr string) *Backend {
+	return &Backend{
+		addr:   addr,
+		events: make(chan string, 16),
+	}
+}
+
+func (b *Backend) IsRemote() bool {
+	return len(b.args) == 0
+}
+
+func (b *Backend) Stderr() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Join(b.stderr, "\n")
+}
+
+func (b *Backend) addStderr(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stderr = append(b.stderr, line)
+	if len(b.stderr) > stderrLines {
+		b.stderr = b.stderr[1:]
+	}
+}
+
+// Start dlv, killing the previous process if there's one, and wait until its rpc server is listening.
+func (b *Backend) Start() error {
+	if b.IsRemote() {
+		return nil
+	}
+	b.Kill()
+
+	log.Printf("Starting dlv-backend: dlv %s", strings.Join(b.args, " "))
+	cmd := exec.Command(
+		"dlv",
+		b.args...,
+	)
+	// The debugged program inherits the environment of dlv.
+	cmd.Env = append(os.Environ(), b.env...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Pdeathsig: syscall.SIGKILL,
+	}
+	stdout, _ := cmd.StdoutPipe()
+	stderr, _ := cmd.StderrPipe()
+	if err := cmd.Start(); err != nil {
+		log.Printf("Error starting dlv-backend: %s", err.Error())
+		return fmt.Errorf("could not start dlv: %w", err)
+	}
+	log.Printf("dlv-backend running with pid %d", cmd.Process.Pid)
+
+	exited := make(chan struct{})
+	b.mu.Lock()
+	b.cmd = cmd
+	b.exited = exited
+	b.stderr = nil
+	b.mu.Unlock()
+
+	// Wait closes the pipes, so it's called only once both have been read to the end.
+	var readers sync.WaitGroup
+	readers.Add(2)
+	addrC := make(chan string, 1)
+	go func() {
+		defer readers.Done()
+		found := false
+		in := bufio.NewScanner(stdout)
+		for in.Scan() {
+			log.Printf("dlv-backend:%s", in.Text())
+			if idx := strings.Index(in.Text(), listeningMsg); idx >= 0 && !found {
+				found = true
+				addrC <- strings.TrimSpace(in.Text()[idx+len(listeningMsg):])
+			}
+		}
+		if err := in.Err(); err != nil {
+			log.Printf("Error: %s", err)
+		}
+		if !found {
+			close(addrC)
+		}
+	}()
+	go func() {
+		defer readers.Done()
+		in := bufio.NewScanner(stderr)
+		for in.Scan() {
+			log.Printf("dlv-backend stderr:%s", in.Text())
+			b.addStderr(in.Text())
+		}
+	}()
+	go func() {
+		readers.Wait()
+		err := cmd.Wait()
+		log.Printf("dlv-backend with pid %d exited: %v", cmd.Process.Pid, err)
+		close(exited)
+	}()
+
+	addr, ok := <-addrC
+	if !ok {
+		<-exited
+		return fmt.Errorf("dlv exited before its rpc server was started:\n%s", b.Stderr())
+	}
+	log.Printf("dlv-backend listening at %s", addr)
+	b.addr = addr
+	return nil
+}
+
+// Kill the dlv process if it's running. dlv is interrupted first, so that it
+// kills the debugged program too.
+func (b *Backend) Kill() {
+	b.mu.Lock()
+	cmd, exited := b.cmd, b.exited
+	b.cmd = nil
+	b.gen++
+	b.mu.Unlock()
+	if cmd == nil {
+		return
+	}
+	cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-exited:
+	case <-time.After(killTimeout):
+		log.Printf("dlv-backend with pid %d didn't exit, killing it", cmd.Process.Pid)
+		cmd.Process.Kill()
+		<-exited
+	}
+}
+
+// Connect to the rpc server. The connection is watched, and a message is sent
+// to Events if it's lost or the dlv process exits.
+func (b *Backend) Connect() (*rpc2.RPCClient, error) {
+	b.mu.Lock()
+	exited := b.exited
+	b.gen++
+	gen := b.gen
+	b.mu.Unlock()
+
+	conn, err := NewClient(b.addr, exited)
+	if err != nil {
+		if exited != nil && isClosed(exited) {
+			return nil, fmt.Errorf("%s\n%s", err.Error(), b.Stderr())
+		}
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-exited:
+			b.report(gen, fmt.Sprintf("dlv exited unexpectedly.\n%s", b.Stderr()))
+		case <-conn.closed:
+			// The connection is also closed when dlv exits, prefer reporting that.
+			time.Sleep(time.Second / 2)
+			if exited != nil && isClosed(exited) {
+				b.report(gen, fmt.Sprintf("dlv exited unexpectedly.\n%s", b.Stderr()))
+			} else {
+				b.report(gen, fmt.Sprintf("Lost connection to dlv at %s.", b.addr))
+			}
+		}
+	}()
+	return rpc2.NewClientFromConn(conn), nil
+}
+
+// Send event unless the connection it concerns has been replaced on purpose.
+func (b *Backend) report(gen int, msg string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen == b.gen {
+		log.Print(msg)
+		b.events <- msg
+	}
+}
+
+// Stop reporting the loss of the current connection, e.g. when quitting.
+func (b *Backend) Forget() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.gen++
+}
+
+// Messages describing a lost backend, sent after a successful Connect.
+func (b *Backend) Events() <-chan string {
+	return b.events
+}
+
+func isClosed(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
@@ -4,19 +4,27 @@ import (
 	"github.com/ilmari-h/dlvtui/nav"
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/go-delve/delve/service/api"
 	"github.com/rivo/tview"
 )
 
 type BreakpointsPage struct {
 	preRenderSelection *nav.UiBreakpoint // Id of selected breakpoint before rerender.
+	preRenderGroup     string            // Selected group before rerender.
 	commandHandler     *CommandHandler
 	treeView           *tview.TreeView
 	widget             *tview.Frame
 	fileList           map[string]*tview.TreeNode
+	groupList          map[string]*tview.TreeNode // Breakpoints created from a regular expression.
 }
 
+// Reference of a tree node containing a group of breakpoints.
+type breakpointGroup string
+
 func NewBreakpointsPage() *BreakpointsPage {
 	root := tview.NewTreeNode(".").
 		SetColor(tcell.ColorDefault)
@@ -52,6 +60,7 @@ func (page *BreakpointsPage) RenderBreakpoints(bps []*nav.UiBreakpoint) {
 		return bps[i].Line < bps[j].Line || bps[i].File < bps[j].File
 	})
 	page.fileList = make(map[string]*tview.TreeNode)
+	page.groupList = make(map[string]*tview.TreeNode)
 	rootNode := page.treeView.GetRoot()
 	rootNode.ClearChildren()
 
@@ -61,8 +70,21 @@ func (page *BreakpointsPage) RenderBreakpoints(bps []*nav.UiBreakpoint) {
 		if bp.ID < 0 {
 			continue
 		}
-		fileNode, ok := page.fileList[bp.File]
-		if !ok {
+		fileNode, ok := page.parentNode(bp)
+		if !ok && bp.Group != "" {
+			fileNode = tview.NewTreeNode("").
+				SetSelectable(true).
+				SetReference(breakpointGroup(bp.Group))
+			rootNode.AddChild(fileNode)
+			page.groupList[bp.Group] = fileNode
+			if page.preRenderGroup == bp.Group {
+				lastSelectedNode = fileNode
+			}
+			fileNode.SetSelectedFunc(func() {
+				fileNode.SetExpanded(!fileNode.IsExpanded())
+			})
+			fileNode.SetColor(tcell.ColorBlack)
+		} else if !ok {
 			fileNode = tview.NewTreeNode(fmt.Sprintf("[%s::b]%s",
 				iToColorS(gConfig.Colors.ListHeaderFg),
 				bp.File,
@@ -94,7 +116,20 @@ func (page *BreakpointsPage) RenderBreakpoints(bps []*nav.UiBreakpoint) {
 
 		current := bp.Line == page.commandHandler.view.navState.CurrentDebuggerPos.Line &&
 			bp.File == page.commandHandler.view.navState.CurrentDebuggerPos.File
-		if current {
+		if bp.Tracepoint {
+			icon := gConfig.Icons.Logpoint
+			if bp.Disabled {
+				icon = gConfig.Icons.LogpointDisabled
+			}
+			bpNode.SetText(fmt.Sprintf("[%s]%s  [%s::i]%s[%s::-]:%d",
+				iToColorS(gConfig.Colors.LogpointFg),
+				icon,
+				iToColorS(gConfig.Colors.VarNameFg),
+				bp.FunctionName,
+				iToColorS(gConfig.Colors.LineFg),
+				bp.Line,
+			))
+		} else if current {
 			bpNode.SetText(fmt.Sprintf("[%s]%s  [%s::b]%s[%s]:%d",
 				iToColorS(gConfig.Colors.BpActiveFg),
 				gConfig.Icons.BpActive,
@@ -114,6 +149,60 @@ func (page *BreakpointsPage) RenderBreakpoints(bps []*nav.UiBreakpoint) {
 			))
 		}
 
+		if scope := goroutineScopeText(bp); scope != "" {
+			bpNode.SetText(fmt.Sprintf("%s [%s]%s",
+				bpNode.GetText(),
+				iToColorS(gConfig.Colors.VarTypeFg),
+				tview.Escape(scope),
+			))
+		}
+		if bp.Cond != "" {
+			bpNode.SetText(fmt.Sprintf("%s [%s]if %s",
+				bpNode.GetText(),
+				iToColorS(gConfig.Colors.VarValueFg),
+				tview.Escape(bp.Cond),
+			))
+		}
+
+		if bp.Tracepoint {
+			msg := bp.LogMessage
+			if msg == "" {
+				msg = strings.Join(bp.Variables, ", ")
+			}
+			bpNode.SetText(fmt.Sprintf("%s [%s]log %q",
+				bpNode.GetText(),
+				iToColorS(gConfig.Colors.LogpointFg),
+				tview.Escape(msg),
+			))
+		}
+		if bp.Temporary {
+			bpNode.SetText(fmt.Sprintf("%s [%s]temporary",
+				bpNode.GetText(),
+				iToColorS(gConfig.Colors.VarAddrFg),
+			))
+		}
+		if len(bp.Actions) > 0 {
+			bpNode.SetText(fmt.Sprintf("%s [%s]do %s",
+				bpNode.GetText(),
+				iToColorS(gConfig.Colors.VarValueFg),
+				tview.Escape(strings.Join(bp.Actions, "; ")),
+			))
+		}
+		if bp.HitCond != "" {
+			bpNode.SetText(fmt.Sprintf("%s [%s]hit %s",
+				bpNode.GetText(),
+				iToColorS(gConfig.Colors.VarValueFg),
+				tview.Escape(bp.HitCond),
+			))
+		}
+		if bp.TotalHitCount > 0 {
+			bpNode.SetText(fmt.Sprintf("%s [%s]%s",
+				bpNode.GetText(),
+				iToColorS(gConfig.Colors.VarAddrFg),
+				hitCountText(bp),
+			))
+		}
+
 		bpNode.SetReference(bp)
 		bpNode.SetSelectable(true)
 		bpNode.SetSelectedFunc(func() {
@@ -125,11 +214,194 @@ func (page *BreakpointsPage) RenderBreakpoints(bps []*nav.UiBreakpoint) {
 		})
 		fileNode.AddChild(bpNode)
 	}
+	page.renderWatchpoints(rootNode)
+	page.renderCatchpoints(rootNode)
+	for group, groupNode := range page.groupList {
+		groupNode.SetText(fmt.Sprintf("[%s::b]/%s/ [%s::-](%d breakpoints)",
+			iToColorS(gConfig.Colors.ListHeaderFg),
+			tview.Escape(group),
+			iToColorS(gConfig.Colors.VarAddrFg),
+			len(groupNode.GetChildren()),
+		))
+	}
 	if lastSelectedNode != nil {
 		page.treeView.SetCurrentNode(lastSelectedNode)
 	}
 }
 
+// Total hit count of a breakpoint followed by the counts per goroutine.
+func hitCountText(bp *nav.UiBreakpoint) string {
+	text := fmt.Sprintf("%d hits", bp.TotalHitCount)
+	if len(bp.HitCount) == 0 {
+		return text
+	}
+	goroutines := make([]int, 0, len(bp.HitCount))
+	for id := range bp.HitCount {
+		if n, err := strconv.Atoi(id); err == nil {
+			goroutines = append(goroutines, n)
+		}
+	}
+	sort.Ints(goroutines)
+	counts := make([]string, 0, len(goroutines))
+	for _, id := range goroutines {
+		counts = append(counts, fmt.Sprintf("g%d: %d", id, bp.HitCount[strconv.Itoa(id)]))
+	}
+	return fmt.Sprintf("%s (%s)", text, strings.Join(counts, ", "))
+}
+
+func (page *BreakpointsPage) renderWatchpoints(rootNode *tview.TreeNode) {
+	wps := []*nav.Watchpoint{}
+	for _, wp := range page.commandHandler.view.navState.Watchpoints {
+		wps = append(wps, wp)
+	}
+	if len(wps) == 0 {
+		return
+	}
+	sort.Slice(wps, func(i, j int) bool {
+		return wps[i].ID < wps[j].ID
+	})
+
+	header := tview.NewTreeNode(fmt.Sprintf("[%s::b]watchpoints",
+		iToColorS(gConfig.Colors.ListHeaderFg),
+	)).
+		SetSelectable(true)
+	header.SetSelectedFunc(func() {
+		header.SetExpanded(!header.IsExpanded())
+	})
+	header.SetColor(tcell.ColorBlack)
+	rootNode.AddChild(header)
+
+	for _, wp := range wps {
+		scope := "scope unknown"
+		if wp.GoroutineID >= 0 {
+			scope = fmt.Sprintf("goroutine %d in %s", wp.GoroutineID, wp.Function)
+		}
+		text := fmt.Sprintf("[%s]%s  [%s]%s [%s](%s) %s",
+			iToColorS(gConfig.Colors.BpFg),
+			gConfig.Icons.Watchpoint,
+			iToColorS(gConfig.Colors.VarNameFg),
+			tview.Escape(wp.WatchExpr),
+			iToColorS(gConfig.Colors.VarTypeFg),
+			watchTypeText(wp.WatchType),
+			tview.Escape(scope),
+		)
+		if wp.TotalHitCount > 0 {
+			text += fmt.Sprintf(" [%s]%d hits", iToColorS(gConfig.Colors.VarAddrFg), wp.TotalHitCount)
+		}
+		wpNode := tview.NewTreeNode(text).
+			SetReference(wp).
+			SetSelectable(true)
+		wpNode.SetColor(tcell.ColorBlack)
+		header.AddChild(wpNode)
+	}
+}
+
+// Panics and other events the program stops on. The ones that can be configured
+// are listed even when they're disabled.
+func (page *BreakpointsPage) renderCatchpoints(rootNode *tview.TreeNode) {
+	created := page.commandHandler.view.navState.Catchpoints
+	if len(created) == 0 {
+		return
+	}
+
+	header := tview.NewTreeNode(fmt.Sprintf("[%s::b]stop on",
+		iToColorS(gConfig.Colors.ListHeaderFg),
+	)).
+		SetSelectable(true)
+	header.SetSelectedFunc(func() {
+		header.SetExpanded(!header.IsExpanded())
+	})
+	header.SetColor(tcell.ColorBlack)
+	rootNode.AddChild(header)
+
+	for _, cp := range catchpoints {
+		bp, ok := created[cp.Name]
+		if !ok && cp.Option == nil {
+			continue
+		}
+		icon := gConfig.Icons.Bp
+		if !ok {
+			icon = gConfig.Icons.BpDisabled
+		}
+		text := fmt.Sprintf("[%s]%s  [%s]%s",
+			iToColorS(gConfig.Colors.BpFg),
+			icon,
+			iToColorS(gConfig.Colors.VarNameFg),
+			cp.Description,
+		)
+		if ok && bp.TotalHitCount > 0 {
+			text += fmt.Sprintf(" [%s]%d hits", iToColorS(gConfig.Colors.VarAddrFg), bp.TotalHitCount)
+		}
+		cpNode := tview.NewTreeNode(text).
+			SetReference(cp).
+			SetSelectable(true)
+		cpNode.SetColor(tcell.ColorBlack)
+		header.AddChild(cpNode)
+	}
+}
+
+func watchTypeText(watchType api.WatchType) string {
+	switch {
+	case watchType&api.WatchRead != 0 && watchType&api.WatchWrite != 0:
+		return "read/write"
+	case watchType&api.WatchRead != 0:
+		return "read"
+	}
+	return "write"
+}
+
+// Node the breakpoint is listed under, either its group or its file.
+func (page *BreakpointsPage) parentNode(bp *nav.UiBreakpoint) (*tview.TreeNode, bool) {
+	if bp.Group != "" {
+		node, ok := page.groupList[bp.Group]
+		return node, ok
+	}
+	node, ok := page.fileList[bp.File]
+	return node, ok
+}
+
+// Breakpoints of the selected group node, nil if something else is selected.
+func (page *BreakpointsPage) selectedGroup() []*nav.UiBreakpoint {
+	selectedNode := page.treeView.GetCurrentNode()
+	if selectedNode == nil {
+		return nil
+	}
+	if _, ok := selectedNode.GetReference().(breakpointGroup); !ok {
+		return nil
+	}
+	bps := []*nav.UiBreakpoint{}
+	for _, child := range selectedNode.GetChildren() {
+		bps = append(bps, child.GetReference().(*nav.UiBreakpoint))
+	}
+	return bps
+}
+
+// Breakpoint of the selected node, nil if a file or a group is selected.
+func (page *BreakpointsPage) SelectedBreakpoint() *nav.UiBreakpoint {
+	selectedNode := page.treeView.GetCurrentNode()
+	if selectedNode == nil {
+		return nil
+	}
+	bp, _ := selectedNode.GetReference().(*nav.UiBreakpoint)
+	return bp
+}
+
+func (page *BreakpointsPage) clearBreakpoint(bp *nav.UiBreakpoint) {
+	if bp.Disabled {
+		page.commandHandler.RunCommand(&ClearBreakpoint{bp, false, bp})
+	} else {
+		page.commandHandler.RunCommand(&ClearBreakpoint{bp, false, nil})
+	}
+}
+
+func (page *BreakpointsPage) toggleBreakpoint(bp *nav.UiBreakpoint) {
+	if !bp.Disabled {
+		page.commandHandler.RunCommand(&ClearBreakpoint{bp, true, nil})
+	} else {
+		page.commandHandler.RunCommand(recreateBreakpoint(bp))
+	}
+}
+
 func (page *BreakpointsPage) GetWidget() tview.Primitive {
 	return page.widget
 }
@@ -141,31 +413,81 @@ func (page *BreakpointsPage) GetName() string {
 func (page *BreakpointsPage) HandleKeyEvent(event *tcell.EventKey) *tcell.EventKey {
 	if keyPressed(event, gConfig.Keys.ClearBreakpoint) {
 		selectedNode := page.treeView.GetCurrentNode()
-		if selectedNode.GetReference() == nil {
+		if wp, ok := selectedNode.GetReference().(*nav.Watchpoint); ok {
+			page.commandHandler.RunCommand(&ClearWatchpoint{wp})
 			return nil
 		}
-		selectedBp := selectedNode.GetReference().(*nav.UiBreakpoint)
-		page.fileList[selectedBp.File].RemoveChild(selectedNode)
-
-		if selectedBp.Disabled {
-			page.commandHandler.RunCommand(&ClearBreakpoint{selectedBp, false, selectedBp})
-		} else {
-			page.commandHandler.RunCommand(&ClearBreakpoint{selectedBp, false, nil})
+		if group := page.selectedGroup(); group != nil {
+			page.treeView.GetRoot().RemoveChild(page.treeView.GetCurrentNode())
+			for _, bp := range group {
+				page.clearBreakpoint(bp)
+			}
+			page.treeView.SetCurrentNode(page.treeView.GetRoot())
+			return nil
+		}
+		selectedBp := page.SelectedBreakpoint()
+		if selectedBp == nil {
+			return nil
 		}
-		page.treeView.SetCurrentNode(page.fileList[selectedBp.File])
+		parent, _ := page.parentNode(selectedBp)
+		parent.RemoveChild(selectedNode)
+		page.clearBreakpoint(selectedBp)
+		page.treeView.SetCurrentNode(parent)
 		return nil
 	} else if keyPressed(event, gConfig.Keys.ToggleBreakpoint) {
-		selectedNode := page.treeView.GetCurrentNode()
-		if selectedNode.GetReference() == nil {
+		if cp, ok := page.treeView.GetCurrentNode().GetReference().(*catchpoint); ok {
+			if cp.Option != nil {
+				page.commandHandler.RunCommand(&ToggleCatchpoint{cp.Name})
+			}
+			return nil
+		}
+		if group := page.selectedGroup(); group != nil {
+			page.preRenderSelection = nil
+			page.preRenderGroup = string(page.treeView.GetCurrentNode().GetReference().(breakpointGroup))
+
+			// Disable the whole group unless all of it is disabled already.
+			enable := true
+			for _, bp := range group {
+				enable = enable && bp.Disabled
+			}
+			for _, bp := range group {
+				if bp.Disabled == enable {
+					page.toggleBreakpoint(bp)
+				}
+			}
+			return nil
+		}
+		selectedBp := page.SelectedBreakpoint()
+		if selectedBp == nil {
 			return nil
 		}
-		selectedBp := selectedNode.GetReference().(*nav.UiBreakpoint)
 		page.preRenderSelection = selectedBp
-		if !selectedBp.Disabled {
-			page.commandHandler.RunCommand(&ClearBreakpoint{selectedBp, true, nil})
-		} else {
-			page.commandHandler.RunCommand(&CreateBreakpoint{selectedBp.Line, selectedBp.File})
+		page.preRenderGroup = ""
+		page.toggleBreakpoint(selectedBp)
+	} else if keyPressed(event, gConfig.Keys.EditActions) {
+		if selectedBp := page.SelectedBreakpoint(); selectedBp != nil {
+			page.preRenderSelection = selectedBp
+			page.commandHandler.view.toCmdModeWithText("actions " + strings.Join(selectedBp.Actions, "; "))
 		}
+		return nil
+	} else if keyPressed(event, gConfig.Keys.EditLogMessage) {
+		if selectedBp := page.SelectedBreakpoint(); selectedBp != nil {
+			page.preRenderSelection = selectedBp
+			page.commandHandler.view.toCmdModeWithText("log " + selectedBp.LogMessage)
+		}
+		return nil
+	} else if keyPressed(event, gConfig.Keys.EditHitCondition) {
+		if selectedBp := page.SelectedBreakpoint(); selectedBp != nil {
+			page.preRenderSelection = selectedBp
+			page.commandHandler.view.toCmdModeWithText("hitcond " + selectedBp.HitCond)
+		}
+		return nil
+	} else if keyPressed(event, gConfig.Keys.EditCondition) {
+		if selectedBp := page.SelectedBreakpoint(); selectedBp != nil {
+			page.preRenderSelection = selectedBp
+			page.commandHandler.view.toCmdModeWithText("cond " + selectedBp.Cond)
+		}
+		return nil
 	}
 	page.treeView.InputHandler()(event, func(p tview.Primitive) {})
 	return nil
@@ -1,14 +1,32 @@
 package main
 
 import (
+	"fmt"
 	"net"
+	"sync"
 	"time"
 
-	"github.com/go-delve/delve/service/rpc2"
 	log "github.com/sirupsen/logrus"
 )
 
-func NewClient(addr string, clientChan chan *rpc2.RPCClient) {
+// Connection that signals when reading from it fails, i.e. when the backend goes away.
+type watchedConn struct {
+	net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (c *watchedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if err != nil {
+		c.once.Do(func() { close(c.closed) })
+	}
+	return n, err
+}
+
+// Connect to the dlv backend at addr, retrying until it accepts connections.
+// Gives up early if exited is closed.
+func NewClient(addr string, exited <-chan struct{}) (*watchedConn, error) {
 
 	attempts := 0
 	for {
@@ -16,12 +34,11 @@ func NewClient(addr string, clientChan chan *rpc2.RPCClient) {
 		time.Sleep(time.Second / 10)
 		if conn != nil {
 			log.Print("Client connection established.")
-			clientChan <- rpc2.NewClientFromConn(conn)
-			break
+			return &watchedConn{Conn: conn, closed: make(chan struct{})}, nil
 		}
 		attempts++
-		if attempts >= 50 {
-			log.Fatalf("Failed to connect client to dlv backend after %d attempts!", attempts)
+		if attempts >= 50 || (exited != nil && isClosed(exited)) {
+			return nil, fmt.Errorf("failed to connect to dlv backend at %s after %d attempts", addr, attempts)
 		}
 		log.Printf("Client connection to %s refused, retry number %d.", addr, attempts)
 	}
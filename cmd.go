@@ -4,9 +4,13 @@ import (
 	"bufio"
 	"github.com/ilmari-h/dlvtui/nav"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/go-delve/delve/service/api"
@@ -15,18 +19,15 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// TODO: make configurable
-var defaultConfig = api.LoadConfig{
-	FollowPointers:     true,
-	MaxVariableRecurse: 5,
-	MaxStringLen:       999,
-	MaxArrayValues:     999,
-	MaxStructFields:    -1,
-}
-
-// Read file from disk.
+// Read file from disk. Sends nil if the file doesn't exist.
 func loadFile(path string, fileChan chan *nav.File) {
 
+	if _, err := os.Stat(path); err != nil {
+		log.Printf("Error loading file %s: %s", path, err)
+		fileChan <- nil
+		return
+	}
+
 	var scanner *bufio.Scanner = nil
 	if gConfig.SyntaxHighlighter != "" {
 		commandArr := strings.Fields(gConfig.SyntaxHighlighter)
@@ -45,6 +46,7 @@ func loadFile(path string, fileChan chan *nav.File) {
 		defer f.Close()
 		if err != nil {
 			log.Printf("Error loading file %s: %s", path, err)
+			fileChan <- nil
 			return
 		}
 		scanner = bufio.NewScanner(f)
@@ -76,6 +78,11 @@ var AvailableCommands = []string{
 	"bs", "breakpoints",
 	"stack",
 	"goroutines",
+	"tests",
+	"run",
+	"output",
+	"find",
+	"send",
 	"locals",
 	"code",
 	"restart",
@@ -83,7 +90,27 @@ var AvailableCommands = []string{
 	"n", "next",
 	"s", "step",
 	"so", "stepout",
+	"break",
+	"tbreak",
+	"rbreak",
+	"watch",
+	"cond",
+	"actions",
+	"print",
+	"bt", "stacktrace",
+	"catch",
+	"panic",
+	"set",
+	"bexport",
+	"bimport",
+	"hitcond",
+	"log",
+	"tracelog",
 	"q", "quit",
+	"detach",
+	"kill",
+	"reconnect",
+	"relaunch",
 }
 
 func StringToLineCommand(s string, args []string) LineCommand {
@@ -99,6 +126,27 @@ func StringToLineCommand(s string, args []string) LineCommand {
 		return &OpenPage{PageIndex: IStackPage}
 	case "goroutines":
 		return &OpenPage{PageIndex: IGoroutinePage}
+	case "tests":
+		return &OpenPage{PageIndex: ITestsPage}
+	case "run":
+		if len(args) == 0 {
+			return nil
+		}
+		return &RunTests{
+			Filter: args[0],
+		}
+	case "output":
+		return &OpenPage{PageIndex: IOutputPage}
+	case "tracelog":
+		return &OpenPage{PageIndex: ITraceLogPage}
+	case "find":
+		return &SearchOutput{
+			Text: strings.Join(args, " "),
+		}
+	case "send":
+		return &SendInput{
+			Text: strings.Join(args, " "),
+		}
 	case "locals":
 		return &OpenPage{PageIndex: IVarsPage}
 	case "code":
@@ -113,8 +161,71 @@ func StringToLineCommand(s string, args []string) LineCommand {
 		return &Step{}
 	case "so", "stepout":
 		return &StepOut{}
+	case "break":
+		return parseBreakCommand(args, false)
+	case "tbreak":
+		return parseBreakCommand(args, true)
+	case "rbreak":
+		if len(args) == 0 {
+			return nil
+		}
+		return &CreateRegexBreakpoints{
+			Regex: strings.Join(args, " "),
+		}
+	case "watch":
+		return parseWatchCommand(args)
+	case "bexport", "bimport":
+		if len(args) == 0 {
+			return nil
+		}
+		if s == "bexport" {
+			return &ExportBreakpoints{Path: args[0]}
+		}
+		return &ImportBreakpoints{Path: args[0]}
+	case "actions":
+		return &EditActions{
+			Actions: splitActions(strings.Join(args, " ")),
+		}
+	case "print":
+		if len(args) == 0 {
+			return nil
+		}
+		return &Print{
+			Expr: strings.Join(args, " "),
+		}
+	case "bt", "stacktrace":
+		return &PrintStack{}
+	case "catch":
+		if len(args) == 0 {
+			return nil
+		}
+		return &ToggleCatchpoint{Name: args[0]}
+	case "panic":
+		return &OpenPage{PageIndex: IPanicPage}
+	case "set":
+		return parseSetCommand(args)
+	case "cond":
+		return &EditCondition{
+			Cond: strings.Join(args, " "),
+		}
+	case "hitcond":
+		return &EditHitCondition{
+			HitCond: strings.Join(args, " "),
+		}
+	case "log":
+		return &EditLogMessage{
+			Message: strings.Join(args, " "),
+		}
 	case "q", "quit":
 		return &Quit{}
+	case "reconnect":
+		return &Connect{}
+	case "relaunch":
+		return &Connect{Launch: true}
+	case "detach":
+		return &Quit{Detach: true}
+	case "kill":
+		return &Quit{Kill: true}
 	}
 	return nil
 }
@@ -122,7 +233,7 @@ func StringToLineCommand(s string, args []string) LineCommand {
 type CommandHandler struct {
 	view      *View
 	app       *tview.Application
-	rpcClient *rpc2.RPCClient
+	rpcClient *rpc2.RPCClient // nil while not connected to the backend.
 }
 
 type LineCommand interface {
@@ -137,10 +248,75 @@ func NewCommandHandler(view *View, app *tview.Application, client *rpc2.RPCClien
 	}
 }
 
+// Commands that resume or restart the target or change its breakpoints.
+func modifiesExecution(cmd LineCommand) bool {
+	switch cmd.(type) {
+	case *Continue, *RunToLine, *Next, *Step, *StepOut, *Restart, *RunTests, *CreateBreakpoint, *CreateBreakpointAt,
+		*CreateRegexBreakpoints, *CreateWatchpoint, *ImportBreakpoints,
+		*EditCondition, *EditHitCondition, *EditLogMessage, *EditActions, *ToggleCatchpoint:
+		return true
+	}
+	return false
+}
+
+// Commands that can be run without a connection to the backend.
+func worksOffline(cmd LineCommand) bool {
+	switch cmd.(type) {
+	case *OpenPage, *OpenFile, *SearchOutput, *SendInput, *Connect, *Quit,
+		*ExportBreakpoints, *Sequence, *SetOption, *Notify:
+		return true
+	}
+	return false
+}
+
+func (commandHandler *CommandHandler) SetClient(client *rpc2.RPCClient) {
+	commandHandler.rpcClient = client
+}
+
+// Whether the command can be run in the current state, notifies if not.
+func (commandHandler *CommandHandler) allowed(cmd LineCommand) bool {
+	if commandHandler.view.readOnly && modifiesExecution(cmd) {
+		go commandHandler.view.showNotification("Target is read-only, execution commands are disabled.", true)
+		return false
+	}
+	if commandHandler.rpcClient == nil && !worksOffline(cmd) {
+		go commandHandler.view.showNotification("Not connected to the dlv backend.", true)
+		return false
+	}
+	return true
+}
+
 func (commandHandler *CommandHandler) RunCommand(cmd LineCommand) {
+	if !commandHandler.allowed(cmd) {
+		return
+	}
 	go cmd.run(commandHandler.view, commandHandler.app, commandHandler.rpcClient)
 }
 
+// Run commands one after another, each one once the previous has finished.
+type Sequence struct {
+	Commands []LineCommand
+}
+
+func (cmd *Sequence) run(view *View, app *tview.Application, _ *rpc2.RPCClient) {
+	for _, c := range cmd.Commands {
+		if !view.cmdHandler.allowed(c) {
+			return
+		}
+		c.run(view, app, view.cmdHandler.rpcClient)
+	}
+}
+
+// Show a message, such as why a command couldn't be parsed.
+type Notify struct {
+	Message string
+	Error   bool
+}
+
+func (cmd *Notify) run(view *View, app *tview.Application, _ *rpc2.RPCClient) {
+	view.showNotification(cmd.Message, cmd.Error)
+}
+
 func applyPrefix(pfx string, arr []string) []string {
 	res := []string{}
 	for _, v := range arr {
@@ -149,16 +325,20 @@ func applyPrefix(pfx string, arr []string) []string {
 	return res
 }
 
+// Paths in arr under directory pfx, relative to it.
 func substractPrefix(pfx string, arr []string) []string {
 	res := []string{}
 	for _, v := range arr {
-		if strings.HasPrefix(v, pfx) {
+		if strings.HasPrefix(v, pfx+"/") {
 			res = append(res, v[len(pfx)+1:])
 		}
 	}
 	return res
 }
 
+// Upper limit for suggestions, there can be tens of thousands of functions.
+const maxSuggestions = 100
+
 func filter(f string, arr []string) []string {
 	res := []string{}
 	for _, v := range arr {
@@ -184,6 +364,33 @@ func (commandHandler *CommandHandler) GetSuggestions(input string) []string {
 			),
 		)
 		return filter(input, opts)
+	case "run":
+		opts := applyPrefix(s+" ^", commandHandler.view.pageView.testsPage.renderedTests)
+		return filter(input, opts)
+	case "break", "tbreak":
+		opts := applyPrefix(s+" ", commandHandler.view.navState.Functions)
+		opts = append(opts, applyPrefix(s+" ",
+			substractPrefix(
+				commandHandler.view.navState.ProjectPath,
+				commandHandler.view.navState.SourceFiles,
+			),
+		)...)
+		res := filter(input, opts)
+		if len(res) > maxSuggestions {
+			res = res[:maxSuggestions]
+		}
+		return res
+	case "set":
+		opts := applyPrefix(s+" ", loadOptionNames)
+		return filter(input, opts)
+	case "catch":
+		opts := []string{}
+		for _, cp := range catchpoints {
+			if cp.Option != nil {
+				opts = append(opts, s+" "+cp.Name)
+			}
+		}
+		return filter(input, opts)
 	case "c", "continue":
 		break
 	}
@@ -191,28 +398,676 @@ func (commandHandler *CommandHandler) GetSuggestions(input string) []string {
 }
 
 type CreateBreakpoint struct {
-	Line int
-	File string
+	Line    int
+	File    string
+	Cond    string // Go expression, the breakpoint stops only if it's true.
+	HitCond string // Condition on the number of hits, e.g. "% 10".
+
+	LogMessage string   // If set, create a logpoint that doesn't stop the program.
+	Group      string   // Regular expression of a set of breakpoints.
+	Actions    []string // Commands run when the breakpoint is hit.
+	Temporary  bool     // Remove the breakpoint the next time the program stops.
+
+	GoroutineID int    // Stop only in this goroutine.
+	Label       string // Stop only in goroutines with this pprof label.
+}
+
+// Command for enabling a disabled breakpoint again with the same settings.
+func recreateBreakpoint(bp *nav.UiBreakpoint) *CreateBreakpoint {
+	return &CreateBreakpoint{
+		Line:    bp.Line,
+		File:    bp.File,
+		Cond:    bp.Cond,
+		HitCond: bp.HitCond,
+
+		LogMessage: bp.LogMessage,
+		Group:      bp.Group,
+		Actions:    bp.Actions,
+
+		GoroutineID: bp.GoroutineID,
+		Label:       bp.Label,
+	}
 }
 
 func (cmd *CreateBreakpoint) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	bp, err := cmd.create(client)
+	if err != nil {
+		view.showNotification(err.Error(), true)
+		return
+	}
+	view.breakpointChan <- bp
+}
+
+func (cmd *CreateBreakpoint) create(client *rpc2.RPCClient) (*nav.UiBreakpoint, error) {
 
 	log.Printf("Creating bp in %s at line %d", cmd.File, cmd.Line)
 
-	res, err := client.CreateBreakpoint(&api.Breakpoint{
-		File:       cmd.File,
+	bp := &api.Breakpoint{
+		File:       toBackendPath(cmd.File),
 		Line:       cmd.Line,
+		Cond:       scopedCond(cmd.Cond, cmd.GoroutineID, cmd.Label),
+		HitCond:    cmd.HitCond,
 		Goroutine:  true,
-		LoadLocals: &defaultConfig,
-		LoadArgs:   &defaultConfig,
+		LoadLocals: &gConfig.LoadConfig,
+		LoadArgs:   &gConfig.LoadConfig,
+	}
+	if err := setLogMessage(bp, cmd.LogMessage); err != nil {
+		return nil, err
+	}
+	res, err := client.CreateBreakpoint(bp)
+
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		return nil, err
+	}
+	localizeBreakpoint(res)
+	res.Cond = cmd.Cond
+	return &nav.UiBreakpoint{
+		Disabled:    false,
+		LogMessage:  cmd.LogMessage,
+		Group:       cmd.Group,
+		Actions:     cmd.Actions,
+		Temporary:   cmd.Temporary,
+		GoroutineID: cmd.GoroutineID,
+		Label:       cmd.Label,
+		Breakpoint:  res,
+	}, nil
+}
+
+// Create breakpoints at a location expression such as main.go:42 or pkg.Function.
+type CreateBreakpointAt struct {
+	Location    string
+	Cond        string
+	Group       string
+	Temporary   bool
+	GoroutineID int
+	Label       string
+}
+
+func (cmd *CreateBreakpointAt) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	// Breakpoints of a profile may already have been restored from the session.
+	existing, err := client.ListBreakpoints(false)
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+	}
+	created, err := cmd.create(client, existing)
+	for _, bp := range created {
+		view.breakpointChan <- bp
+	}
+	if err != nil {
+		view.showNotification(err.Error(), true)
+	}
+}
+
+// Create a breakpoint on each line of the location that doesn't have one of the
+// existing breakpoints yet. Stops at the first breakpoint that can't be created.
+func (cmd *CreateBreakpointAt) create(client *rpc2.RPCClient, existing []*api.Breakpoint) ([]*nav.UiBreakpoint, error) {
+	locs, err := client.FindLocation(api.EvalScope{GoroutineID: -1}, cmd.Location, false, backendPathRules())
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		return nil, fmt.Errorf("Could not find location %s: %s", cmd.Location, err.Error())
+	}
+	created := []*nav.UiBreakpoint{}
+	for _, loc := range locs {
+		if hasBreakpointAt(existing, loc.File, loc.Line) {
+			continue
+		}
+		create := CreateBreakpoint{
+			Line:        loc.Line,
+			File:        toLocalPath(loc.File),
+			Cond:        cmd.Cond,
+			Group:       cmd.Group,
+			Temporary:   cmd.Temporary,
+			GoroutineID: cmd.GoroutineID,
+			Label:       cmd.Label,
+		}
+		bp, err := create.create(client)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, bp)
+	}
+	return created, nil
+}
+
+func hasBreakpointAt(bps []*api.Breakpoint, file string, line int) bool {
+	for _, bp := range bps {
+		if bp.File == file && bp.Line == line {
+			return true
+		}
+	}
+	return false
+}
+
+func hasBreakpointID(bps []*api.Breakpoint, id int) bool {
+	for _, bp := range bps {
+		if bp.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Create a breakpoint on every function matching a regular expression. The
+// breakpoints are grouped together in the breakpoints page.
+type CreateRegexBreakpoints struct {
+	Regex string
+}
+
+func (cmd *CreateRegexBreakpoints) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	if _, err := regexp.Compile(cmd.Regex); err != nil {
+		view.showNotification(fmt.Sprintf("Invalid regular expression: %s", err.Error()), true)
+		return
+	}
+	fns, err := client.ListFunctions(cmd.Regex)
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		view.showNotification(err.Error(), true)
+		return
+	}
+	if len(fns) == 0 {
+		view.showNotification(fmt.Sprintf("No functions match %s.", cmd.Regex), true)
+		return
+	}
+	existing, err := client.ListBreakpoints(false)
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+	}
+	created := []*nav.UiBreakpoint{}
+	failed := 0
+	for _, fn := range fns {
+		create := CreateBreakpointAt{Location: fn, Group: cmd.Regex}
+		bps, err := create.create(client, existing)
+		if err != nil {
+			log.Printf("Error creating breakpoint in %s: %s", fn, err.Error())
+			failed++
+		}
+		created = append(created, bps...)
+	}
+	for _, bp := range created {
+		view.breakpointChan <- bp
+	}
+	if failed > 0 {
+		view.showNotification(fmt.Sprintf("Created %d breakpoints in functions matching %s, %d functions failed. See the log for details.",
+			len(created), cmd.Regex, failed), true)
+		return
+	}
+	view.showNotification(fmt.Sprintf("Created %d breakpoints in functions matching %s.", len(created), cmd.Regex), false)
+}
+
+// Stop when the memory of an expression, evaluated in the current stack frame,
+// is read or written.
+type CreateWatchpoint struct {
+	Expr string
+	Type api.WatchType
+}
+
+// Parse arguments of the form [-r|-w|-rw] expr. Watching for writes is the
+// default, since variables on the stack can't be watched for reads.
+func parseWatchCommand(args []string) LineCommand {
+	if len(args) == 0 {
+		return nil
+	}
+	watchType := api.WatchWrite
+	switch args[0] {
+	case "-r":
+		watchType = api.WatchRead
+		args = args[1:]
+	case "-w":
+		args = args[1:]
+	case "-rw":
+		watchType = api.WatchRead | api.WatchWrite
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	return &CreateWatchpoint{
+		Expr: strings.Join(args, " "),
+		Type: watchType,
+	}
+}
+
+func (cmd *CreateWatchpoint) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	state := view.navState.DbgState
+	if state == nil || state.CurrentThread == nil {
+		view.showNotification("Watchpoints can only be set while the program is stopped.", true)
+		return
+	}
+
+	// Evaluate the expression in the selected stack frame.
+	scope, function := view.frameScope()
+	res, err := client.CreateWatchpoint(scope, cmd.Expr, cmd.Type)
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		view.showNotification(fmt.Sprintf("Could not watch %s: %s", cmd.Expr, err.Error()), true)
+		return
+	}
+	wp := &nav.Watchpoint{GoroutineID: scope.GoroutineID, Breakpoint: res}
+	if function != nil {
+		wp.Function = function.Name()
+	}
+	view.watchpointChan <- wp
+}
+
+type ClearWatchpoint struct {
+	Watchpoint *nav.Watchpoint
+}
+
+func (cmd *ClearWatchpoint) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	if _, err := client.ClearBreakpoint(cmd.Watchpoint.ID); err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		view.showNotification(err.Error(), true)
+		return
+	}
+	removed := *cmd.Watchpoint
+	removed.Removed = true
+	view.watchpointChan <- &removed
+}
+
+// Set the condition of the selected breakpoint. If no breakpoint is selected,
+// one is created on the current line of the code page.
+type EditCondition struct {
+	Cond string
+}
+
+func (cmd *EditCondition) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	amendSelectedBreakpoint(view, app, client, "Invalid condition", func(bp *nav.UiBreakpoint) error {
+		bp.Cond = cmd.Cond
+		return nil
+	})
+}
+
+// Set the hit count condition of the selected breakpoint, for example "100",
+// ">= 5" or "% 10".
+type EditHitCondition struct {
+	HitCond string
+}
+
+func (cmd *EditHitCondition) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	amendSelectedBreakpoint(view, app, client, "Invalid hit condition", func(bp *nav.UiBreakpoint) error {
+		bp.HitCond = cmd.HitCond
+		return nil
+	})
+}
+
+// Make the selected breakpoint a logpoint, which writes Message to the trace
+// log instead of stopping. An empty message makes it a breakpoint again.
+type EditLogMessage struct {
+	Message string
+}
+
+func (cmd *EditLogMessage) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	amendSelectedBreakpoint(view, app, client, "Invalid log message", func(bp *nav.UiBreakpoint) error {
+		bp.LogMessage = cmd.Message
+		return setLogMessage(bp.Breakpoint, cmd.Message)
+	})
+}
+
+func amendSelectedBreakpoint(
+	view *View,
+	app *tview.Application,
+	client *rpc2.RPCClient,
+	errPrefix string,
+	amend func(bp *nav.UiBreakpoint) error,
+) {
+	bp := view.selectedBreakpoint()
+	if bp == nil {
+		if view.navState.CurrentFile == nil {
+			view.showNotification("No breakpoint selected.", true)
+			return
+		}
+		settings := nav.UiBreakpoint{Breakpoint: &api.Breakpoint{}}
+		if err := amend(&settings); err != nil {
+			view.showNotification(fmt.Sprintf("%s: %s", errPrefix, err.Error()), true)
+			return
+		}
+		create := CreateBreakpoint{
+			Line:    view.navState.CurrentLine() + 1, // Using 1 based indices on the backend.
+			File:    view.navState.CurrentFile.Path,
+			Cond:    settings.Cond,
+			HitCond: settings.HitCond,
+
+			LogMessage: settings.LogMessage,
+		}
+		create.run(view, app, client)
+		return
+	}
+
+	// Settings of a disabled breakpoint are checked once it's enabled again.
+	backendBp := *bp.Breakpoint
+	amended := *bp
+	amended.Breakpoint = &backendBp
+	if err := amend(&amended); err != nil {
+		view.showNotification(fmt.Sprintf("%s: %s", errPrefix, err.Error()), true)
+		return
+	}
+	if !amended.Disabled {
+		req := backendBp
+		req.File = toBackendPath(req.File)
+		req.Cond = scopedCond(req.Cond, amended.GoroutineID, amended.Label)
+		if err := client.AmendBreakpoint(&req); err != nil {
+			log.Printf("rpc error: %s", err.Error())
+			view.showNotification(fmt.Sprintf("%s: %s", errPrefix, err.Error()), true)
+			restoreAmendedBreakpoint(view, client, bp)
+			return
+		}
+	}
+	view.breakpointChan <- &amended
+}
+
+// The backend clears the condition of a breakpoint when amending it fails, so
+// the previous settings are sent again. If that fails too, the breakpoint is
+// shown as the backend has it.
+func restoreAmendedBreakpoint(view *View, client *rpc2.RPCClient, bp *nav.UiBreakpoint) {
+	req := *bp.Breakpoint
+	req.File = toBackendPath(req.File)
+	req.Cond = scopedCond(req.Cond, bp.GoroutineID, bp.Label)
+	err := client.AmendBreakpoint(&req)
+	if err == nil {
+		return
+	}
+	log.Printf("rpc error: %s", err.Error())
+	res, err := client.GetBreakpoint(bp.ID)
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		return
+	}
+	localizeBreakpoint(res)
+	current := *bp
+	current.Breakpoint = res
+	current.GoroutineID, current.Label = 0, ""
+	view.breakpointChan <- &current
+}
+
+// Write breakpoints to a JSON or YAML file, depending on its extension.
+type ExportBreakpoints struct {
+	Path string
+}
+
+func (cmd *ExportBreakpoints) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	bps := view.navState.SavedBreakpoints()
+	if err := writeBreakpointsFile(cmd.Path, view.navState.ProjectPath, bps); err != nil {
+		log.Printf("Error exporting breakpoints: %s", err)
+		view.showNotification(fmt.Sprintf("Could not export breakpoints: %s", err), true)
+		return
+	}
+	view.showNotification(fmt.Sprintf("Exported %d breakpoints to %s.", len(bps), cmd.Path), false)
+}
+
+// Create breakpoints from a file written by ExportBreakpoints.
+type ImportBreakpoints struct {
+	Path string
+}
+
+func (cmd *ImportBreakpoints) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	bps, err := readBreakpointsFile(cmd.Path, view.navState.ProjectPath)
+	if err != nil {
+		log.Printf("Error importing breakpoints: %s", err)
+		view.showNotification(fmt.Sprintf("Could not import breakpoints: %s", err), true)
+		return
+	}
+
+	imported := 0
+	failed := []string{}
+	for _, saved := range bps {
+		if _, ok := view.navState.Breakpoints[saved.File][saved.Line]; ok {
+			continue
+		}
+		if err := restoreBreakpoint(view, client, saved); err != nil {
+			failed = append(failed, fmt.Sprintf("%s:%d (%s)", saved.File, saved.Line, err))
+			continue
+		}
+		imported++
+	}
+	if len(failed) > 0 {
+		view.showNotification(fmt.Sprintf("Imported %d breakpoints, could not resolve %d: %s",
+			imported, len(failed), strings.Join(failed, ", ")), true)
+		return
+	}
+	view.showNotification(fmt.Sprintf("Imported %d breakpoints from %s.", imported, cmd.Path), false)
+}
+
+// Set the commands run when the selected breakpoint is hit.
+type EditActions struct {
+	Actions []string
+}
+
+// Split a list of commands separated by semicolons, such as "print x; bt; c".
+func splitActions(s string) []string {
+	actions := []string{}
+	for _, a := range strings.Split(s, ";") {
+		if a = strings.TrimSpace(a); a != "" {
+			actions = append(actions, a)
+		}
+	}
+	return actions
+}
+
+func (cmd *EditActions) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	amendSelectedBreakpoint(view, app, client, "Invalid action", func(bp *nav.UiBreakpoint) error {
+		for _, a := range cmd.Actions {
+			if parseCommand(a) == nil {
+				return fmt.Errorf("unknown command %s", a)
+			}
+		}
+		bp.Actions = cmd.Actions
+		return nil
 	})
+}
+
+// Start or stop breaking on one of the catchpoints that can be configured, such
+// as "panic" for all panics.
+type ToggleCatchpoint struct {
+	Name string
+}
 
+func (cmd *ToggleCatchpoint) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	cp := findCatchpoint(cmd.Name)
+	if cp == nil || cp.Option == nil {
+		view.showNotification(fmt.Sprintf("Unknown event %s, expected panic or exit.", cmd.Name), true)
+		return
+	}
+	if bp, ok := view.navState.Catchpoints[cp.Name]; ok {
+		if _, err := client.ClearBreakpoint(bp.ID); err != nil {
+			log.Printf("rpc error: %s", err.Error())
+			view.showNotification(err.Error(), true)
+			return
+		}
+		*cp.Option = false
+	} else {
+		if err := createCatchpoint(client, cp); err != nil {
+			log.Printf("rpc error: %s", err.Error())
+			view.showNotification(fmt.Sprintf("Could not stop on %s: %s", cp.Description, err.Error()), true)
+			return
+		}
+		*cp.Option = true
+	}
+	list := GetBreakpoints{}
+	list.run(view, app, client)
+}
+
+// Options for loading variables that can be changed with :set.
+var loadOptionNames = []string{
+	"followpointers",
+	"maxvariablerecurse",
+	"maxstringlen",
+	"maxarrayvalues",
+	"maxstructfields",
+}
+
+// Change a limit for loading variables, e.g. ":set maxstringlen 4096".
+// Without a name, the current values are shown.
+type SetOption struct {
+	Name  string
+	Value string
+}
+
+// Parse "name value" or "name=value".
+func parseSetCommand(args []string) LineCommand {
+	if len(args) == 0 {
+		return &SetOption{}
+	}
+	name, value, ok := strings.Cut(args[0], "=")
+	if !ok && len(args) > 1 {
+		value = args[1]
+	}
+	return &SetOption{Name: strings.ToLower(name), Value: value}
+}
+
+func loadOptionsText() string {
+	cfg := gConfig.LoadConfig
+	return fmt.Sprintf("followpointers=%t maxvariablerecurse=%d maxstringlen=%d maxarrayvalues=%d maxstructfields=%d",
+		cfg.FollowPointers, cfg.MaxVariableRecurse, cfg.MaxStringLen, cfg.MaxArrayValues, cfg.MaxStructFields)
+}
+
+func (cmd *SetOption) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	if cmd.Name == "" {
+		view.showNotification(loadOptionsText(), false)
+		return
+	}
+
+	cfg := &gConfig.LoadConfig
+	var err error
+	switch cmd.Name {
+	case "followpointers":
+		cfg.FollowPointers, err = strconv.ParseBool(cmd.Value)
+	case "maxvariablerecurse":
+		cfg.MaxVariableRecurse, err = strconv.Atoi(cmd.Value)
+	case "maxstringlen":
+		cfg.MaxStringLen, err = strconv.Atoi(cmd.Value)
+	case "maxarrayvalues":
+		cfg.MaxArrayValues, err = strconv.Atoi(cmd.Value)
+	case "maxstructfields":
+		cfg.MaxStructFields, err = strconv.Atoi(cmd.Value)
+	default:
+		view.showNotification(fmt.Sprintf("Unknown option %s, expected one of %s.",
+			cmd.Name, strings.Join(loadOptionNames, ", ")), true)
+		return
+	}
+	if err != nil {
+		view.showNotification(fmt.Sprintf("Invalid value %q for %s.", cmd.Value, cmd.Name), true)
+		return
+	}
+	log.Printf("Set %s to %s", cmd.Name, cmd.Value)
+	if client == nil {
+		return
+	}
+	client.SetReturnValuesLoadConfig(cfg)
+
+	// Breakpoints load their arguments and locals with the limits they were created with.
+	for _, bp := range view.navState.GetAllBreakpoints() {
+		if bp.ID < 0 || bp.Disabled || bp.Tracepoint {
+			continue
+		}
+		req := *bp.Breakpoint
+		req.File = toBackendPath(req.File)
+		req.Cond = scopedCond(req.Cond, bp.GoroutineID, bp.Label)
+		req.LoadArgs = cfg
+		req.LoadLocals = cfg
+		if err := client.AmendBreakpoint(&req); err != nil {
+			log.Printf("rpc error: %s", err.Error())
+		}
+	}
+
+	// Load the variables of the current goroutine again with the new limits.
+	state := view.navState.DbgState
+	if state != nil && state.CurrentThread != nil && !view.nwBlocking {
+		reload := SwitchGoroutines{Id: state.CurrentThread.GoroutineID}
+		reload.run(view, app, client)
+	}
+}
+
+// Load a variable of the locals page again with larger limits than the ones
+// in the configuration.
+type LoadMore struct {
+	Expr string
+	Var  api.Variable
+	Node *tview.TreeNode
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Ten times the configured limits or the amount already loaded of the
+// variable, whichever is larger, so that loading again gets more of it.
+func loadMoreConfig(vr *api.Variable) api.LoadConfig {
+	cfg := gConfig.LoadConfig
+	cfg.MaxStringLen = 10 * maxInt(cfg.MaxStringLen, len(vr.Value))
+	cfg.MaxArrayValues = 10 * maxInt(cfg.MaxArrayValues, len(vr.Children))
+	if cfg.MaxStructFields >= 0 {
+		cfg.MaxStructFields = 10 * maxInt(cfg.MaxStructFields, len(vr.Children))
+	}
+	return cfg
+}
+
+func (cmd *LoadMore) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	state := view.navState.DbgState
+	if state == nil || state.CurrentThread == nil {
+		view.showNotification("The program is not stopped.", true)
+		return
+	}
+	scope, _ := view.frameScope()
+	v, err := client.EvalVariable(scope, cmd.Expr, loadMoreConfig(&cmd.Var))
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		view.showNotification(fmt.Sprintf("Could not load %s: %s", cmd.Expr, err.Error()), true)
+		return
+	}
+	v.Name = cmd.Var.Name
+	view.varChan <- &LoadedVariable{Node: cmd.Node, Var: v}
+}
+
+// Evaluate an expression in the current stack frame and write it to the output page.
+type Print struct {
+	Expr string
+}
+
+func (cmd *Print) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	scope := api.EvalScope{GoroutineID: -1}
+	if view.navState.DbgState != nil && view.navState.DbgState.CurrentThread != nil {
+		scope.GoroutineID = view.navState.DbgState.CurrentThread.GoroutineID
+	}
+	v, err := client.EvalVariable(scope, cmd.Expr, gConfig.LoadConfig)
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		view.entryChan <- &OutputEntry{Prefix: cmd.Expr, Text: err.Error()}
+		return
+	}
+	view.entryChan <- &OutputEntry{Prefix: cmd.Expr, Text: "= " + v.SinglelineString()}
+}
+
+// Write the stack of the current goroutine to the output page.
+type PrintStack struct {
+}
+
+func (cmd *PrintStack) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	state := view.navState.DbgState
+	if state == nil || state.CurrentThread == nil {
+		view.showNotification("The program is not stopped.", true)
+		return
+	}
+	stack, err := client.Stacktrace(state.CurrentThread.GoroutineID, 50, api.StacktraceSimple, nil)
 	if err != nil {
 		log.Printf("rpc error: %s", err.Error())
 		view.showNotification(err.Error(), true)
 		return
 	}
-	view.breakpointChan <- &nav.UiBreakpoint{false, res}
+	localizeStack(stack)
+	for i, sf := range stack {
+		name := "?"
+		if sf.Function != nil {
+			name = sf.Function.Name()
+		}
+		view.entryChan <- &OutputEntry{
+			Prefix: fmt.Sprintf("#%d", i),
+			Text:   fmt.Sprintf("%s %s:%d", name, sf.File, sf.Line),
+		}
+	}
 }
 
 type OpenPage struct {
@@ -251,7 +1106,13 @@ func (cmd *OpenFile) run(view *View, app *tview.Application, client *rpc2.RPCCli
 		view.fileChan <- val
 		return
 	}
-	go loadFile(absPath, view.fileChan)
+	ch := make(chan *nav.File, 1)
+	loadFile(absPath, ch)
+	if file := <-ch; file != nil {
+		view.fileChan <- file
+	} else {
+		view.notifyFileNotFound(absPath)
+	}
 }
 
 type ClearBreakpoint struct {
@@ -275,33 +1136,144 @@ func (cmd *ClearBreakpoint) run(view *View, app *tview.Application, client *rpc2
 		view.showNotification(err.Error(), true)
 		return
 	}
+	localizeBreakpoint(res)
+	res.Cond = cmd.Breakpoint.Cond
 	if !cmd.Disable {
 		res.ID = -1 // Mark as deleted
 	}
-	view.breakpointChan <- &nav.UiBreakpoint{cmd.Disable, res}
+	cleared := *cmd.Breakpoint
+	cleared.Disabled = cmd.Disable
+	cleared.Breakpoint = res
+	view.breakpointChan <- &cleared
 }
 
 type Quit struct {
+	Detach bool // Leave the target running.
+	Kill   bool // Kill the target.
 }
 
 func (cmd *Quit) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+
+	// The backend outlives this client when it was not started by it, so ask what to do with the target.
+	if view.remote && !cmd.Detach && !cmd.Kill {
+		view.showPrompt("Quit: (d)etach and leave the target running, or (k)ill it?", map[rune]LineCommand{
+			'd': &Quit{Detach: true},
+			'k': &Quit{Kill: true},
+		})
+		return
+	}
+
+	view.backend.Forget()
+	var err error
+	if client == nil {
+		// Nothing to detach from.
+	} else if cmd.Kill {
+		err = client.Detach(true)
+	} else if cmd.Detach && client.IsMulticlient() {
+		err = client.Disconnect(true)
+	} else if cmd.Detach {
+		err = client.Detach(false)
+	}
+	if err != nil {
+		log.Printf("rpc error while quitting: %s", err.Error())
+	}
 	app.Stop()
 }
 
 type Continue struct {
 }
 
+// Continue the program until it stops. The client keeps continuing past
+// tracepoints, their hits are reported before the state the program actually
+// stopped in.
+func continueTracing(view *View, client *rpc2.RPCClient) *api.DebuggerState {
+	var res *api.DebuggerState
+	for state := range client.Continue() {
+		reportTraceHits(view, state)
+		res = state
+	}
+	return res
+}
+
+func reportTraceHits(view *View, state *api.DebuggerState) {
+	for _, th := range state.Threads {
+		if th.Breakpoint != nil && th.Breakpoint.Tracepoint {
+			bp := *th.Breakpoint
+			localizeBreakpoint(&bp)
+			view.traceChan <- &TraceHit{
+				Breakpoint:  &bp,
+				Info:        th.BreakpointInfo,
+				GoroutineID: th.GoroutineID,
+			}
+		}
+	}
+}
+
+// Whether the program stopped only because of tracepoints.
+func stoppedOnTracepoints(state *api.DebuggerState) bool {
+	if state == nil || state.Exited {
+		return false
+	}
+	hit := false
+	for _, th := range state.Threads {
+		if th.Breakpoint == nil {
+			continue
+		}
+		if !th.Breakpoint.Tracepoint {
+			return false
+		}
+		hit = true
+	}
+	return hit
+}
+
+// Finish a step that was interrupted by tracepoints. Continuing resumes the
+// step that is in progress.
+func stepTracing(view *View, client *rpc2.RPCClient, state *api.DebuggerState) *api.DebuggerState {
+	if !stoppedOnTracepoints(state) {
+		return state
+	}
+	reportTraceHits(view, state)
+	return continueTracing(view, client)
+}
+
 func (cmd *Continue) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
 
 	view.renderPendingContinue()
 	view.SetBlocking(true)
-
-	res := <-client.Continue()
+	res := skipOutsideLabelScope(view, client, continueTracing(view, client))
 	view.SetBlocking(false)
 
 	debuggerMoveCommand(view, app, client, res)
 }
 
+// Continue until the program reaches a line, using a temporary breakpoint.
+type RunToLine struct {
+	Line int
+	File string
+}
+
+func (cmd *RunToLine) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	// The temporary breakpoint is only created in the backend, so that it
+	// doesn't replace a disabled breakpoint on the line. It's cleared once the
+	// program stops, whether it was hit or not.
+	if bp, ok := view.navState.Breakpoints[cmd.File][cmd.Line]; !ok || bp.Disabled {
+		create := CreateBreakpoint{Line: cmd.Line, File: cmd.File, Temporary: true}
+		bp, err := create.create(client)
+		if err != nil {
+			view.showNotification(err.Error(), true)
+			return
+		}
+		defer func() {
+			if _, err := client.ClearBreakpoint(bp.ID); err != nil {
+				log.Printf("rpc error: %s", err.Error())
+			}
+		}()
+	}
+	cont := Continue{}
+	cont.run(view, app, client)
+}
+
 type GetBreakpoints struct {
 }
 
@@ -313,8 +1285,11 @@ func (cmd *GetBreakpoints) run(view *View, app *tview.Application, client *rpc2.
 		return
 	}
 	for i := range bps {
-		view.breakpointChan <- &nav.UiBreakpoint{false, bps[i]}
+		if bps[i].WatchExpr == "" {
+			localizeBreakpoint(bps[i])
+		}
 	}
+	view.bpListChan <- bps
 }
 
 type Next struct {
@@ -328,6 +1303,8 @@ func (cmd *Next) run(view *View, app *tview.Application, client *rpc2.RPCClient)
 		log.Printf("rpc error: %s", nerr.Error())
 		return
 	}
+	nres = skipOutsideLabelScope(view, client, stepTracing(view, client, nres))
+	localizeState(nres)
 
 	if nres.Exited {
 		msg := fmt.Sprintf("Program has finished with exit status %d.", nres.ExitStatus)
@@ -336,18 +1313,36 @@ func (cmd *Next) run(view *View, app *tview.Application, client *rpc2.RPCClient)
 		return
 	}
 
-	sres, serr := client.Stacktrace(nres.CurrentThread.GoroutineID, 5, api.StacktraceSimple, &defaultConfig)
+	sres, serr := client.Stacktrace(nres.CurrentThread.GoroutineID, 5, api.StacktraceSimple, &gConfig.LoadConfig)
 
 	if serr != nil {
 		log.Printf("rpc error: %s", serr.Error())
 		return
 	}
+	localizeStack(sres)
 
 	// Run ListGoroutines-command when ever new Goroutines may have been started.
 	lg := ListGoroutines{}
 	go lg.run(view, app, client)
 
-	view.dbgMoveChan <- &DebuggerMove{nres, sres}
+	view.dbgMoveChan <- &DebuggerMove{DbgState: nres, Stack: sres, Breakpoints: listBreakpointsAfterStop(client, nres)}
+}
+
+// Hit counts of all breakpoints change while the program runs, so they're
+// fetched again whenever it stops.
+func listBreakpointsAfterStop(client *rpc2.RPCClient, state *api.DebuggerState) []*api.Breakpoint {
+	bps, err := client.ListBreakpoints(false)
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		if state.CurrentThread.Breakpoint != nil {
+			return []*api.Breakpoint{state.CurrentThread.Breakpoint}
+		}
+		return []*api.Breakpoint{}
+	}
+	for i := range bps {
+		localizeBreakpoint(bps[i])
+	}
+	return bps
 }
 
 func debuggerMoveCommand(view *View, app *tview.Application, client *rpc2.RPCClient, cmdRes *api.DebuggerState) {
@@ -356,24 +1351,51 @@ func debuggerMoveCommand(view *View, app *tview.Application, client *rpc2.RPCCli
 		view.notifyProgramEnded(cmdRes.ExitStatus)
 		return
 	}
+	localizeState(cmdRes)
+	if cmdRes.CurrentThread == nil {
+		if cmdRes.Err != nil {
+			log.Printf("rpc error: %s", cmdRes.Err.Error())
+			view.showNotification(cmdRes.Err.Error(), true)
+		}
+		return
+	}
 
-	sres, serr := client.Stacktrace(cmdRes.CurrentThread.GoroutineID, 5, api.StacktraceSimple, &defaultConfig)
+	sres, serr := client.Stacktrace(cmdRes.CurrentThread.GoroutineID, 5, api.StacktraceSimple, &gConfig.LoadConfig)
 
 	if serr != nil {
 		log.Printf("rpc error: %s", serr.Error())
 		return
 	}
+	localizeStack(sres)
+	move := &DebuggerMove{DbgState: cmdRes, Stack: sres}
+
+	// Stopped on a panic, load the whole stack to find the code that caused it.
+	if cp := findCatchpoint(breakpointName(cmdRes.CurrentThread.Breakpoint)); cp != nil {
+		stop, err := loadPanicStop(view, client, cp, cmdRes.CurrentThread)
+		if err != nil {
+			log.Printf("rpc error: %s", err.Error())
+		} else {
+			move.Panic = stop
+			move.Stack = stop.Stack
+		}
+	}
+	file, line := move.Location()
 
 	// If file about to move has not been loaded, load it now.
-	if view.navState.FileCache[cmdRes.CurrentThread.File] == nil {
+	if view.navState.FileCache[file] == nil {
 		ch := make(chan *nav.File)
-		go loadFile(cmdRes.CurrentThread.File, ch)
+		go loadFile(file, ch)
 
 		// Block until file loaded so it can be opened.
-		file := <-ch
-		view.OpenFile(file, cmdRes.CurrentThread.Line-1)
+		loaded := <-ch
+		if loaded != nil {
+			view.OpenFile(loaded, line-1)
+		} else {
+			view.notifyFileNotFound(file)
+		}
 	}
-	view.dbgMoveChan <- &DebuggerMove{cmdRes, sres}
+	move.Breakpoints = listBreakpointsAfterStop(client, cmdRes)
+	view.dbgMoveChan <- move
 
 }
 
@@ -387,6 +1409,7 @@ func (cmd *Step) run(view *View, app *tview.Application, client *rpc2.RPCClient)
 		log.Printf("rpc error: %s", nerr.Error())
 		return
 	}
+	nres = skipOutsideLabelScope(view, client, stepTracing(view, client, nres))
 
 	debuggerMoveCommand(view, app, client, nres)
 }
@@ -401,6 +1424,7 @@ func (cmd *StepOut) run(view *View, app *tview.Application, client *rpc2.RPCClie
 		log.Printf("rpc error: %s", nerr.Error())
 		return
 	}
+	nres = skipOutsideLabelScope(view, client, stepTracing(view, client, nres))
 
 	debuggerMoveCommand(view, app, client, nres)
 }
@@ -414,6 +1438,9 @@ func (cmd *ListGoroutines) run(view *View, app *tview.Application, client *rpc2.
 		log.Printf("rpc error: %s", lerr.Error())
 		return
 	}
+	for _, g := range lres {
+		localizeGoroutine(g)
+	}
 	log.Printf("Fetched active goroutines: %v", lres)
 	view.goroutineChan <- lres
 }
@@ -430,16 +1457,18 @@ func (cmd *SwitchGoroutines) run(view *View, app *tview.Application, client *rpc
 		view.showNotification(err.Error(), true)
 		return
 	}
-	sres, serr := client.Stacktrace(res.CurrentThread.GoroutineID, 5, api.StacktraceSimple, &defaultConfig)
+	localizeState(res)
+	sres, serr := client.Stacktrace(res.CurrentThread.GoroutineID, 5, api.StacktraceSimple, &gConfig.LoadConfig)
 
 	if serr != nil {
 		log.Printf("rpc error: %s", serr.Error())
 		return
 	}
+	localizeStack(sres)
 
 	log.Printf("Switched to goroutine %d.", res.Pid)
 
-	view.dbgMoveChan <- &DebuggerMove{res, sres}
+	view.dbgMoveChan <- &DebuggerMove{DbgState: res, Stack: sres}
 }
 
 type Restart struct {
@@ -453,3 +1482,207 @@ func (cmd *Restart) run(view *View, app *tview.Application, client *rpc2.RPCClie
 	}
 	view.SetBlocking(false)
 }
+
+// Matches the names of functions the testing package runs.
+var testFunctionsRe = `\.(Test|Benchmark|Example)([^a-z.][^.]*)?$`
+
+type ListTests struct {
+}
+
+func (cmd *ListTests) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	fns, err := client.ListFunctions(testFunctionsRe)
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		view.showNotification(err.Error(), true)
+		return
+	}
+
+	seen := make(map[string]bool)
+	tests := []string{}
+	for _, fn := range fns {
+		// Skip methods and functions of the testing package itself.
+		if strings.Contains(fn, "(") || strings.HasPrefix(fn, "testing.") {
+			continue
+		}
+		name := fn[strings.LastIndex(fn, ".")+1:]
+		// TestMain runs the tests, it isn't one.
+		if name == "TestMain" {
+			continue
+		}
+		if !seen[name] {
+			seen[name] = true
+			tests = append(tests, name)
+		}
+	}
+	sort.Strings(tests)
+	log.Printf("Found tests: %v", tests)
+	view.testsChan <- tests
+}
+
+type RunTests struct {
+	Filter string
+	Bench  bool // Run the benchmarks matching Filter instead of the tests.
+}
+
+func (cmd *RunTests) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	if !testMode {
+		view.showNotification("Tests can only be run when debugging with -test.", true)
+		return
+	}
+	if _, err := regexp.Compile(cmd.Filter); err != nil {
+		view.showNotification(fmt.Sprintf("Invalid test filter: %s", err.Error()), true)
+		return
+	}
+
+	log.Printf("Restarting tests with filter %s", cmd.Filter)
+	flags := []string{"-test.run", cmd.Filter}
+	if cmd.Bench {
+		flags = []string{"-test.bench", cmd.Filter, "-test.run", "^$"}
+	}
+	args := append(flags, progOpts.Args...)
+	_, err := client.RestartFrom(false, "", true, args, progOpts.Redirects, false)
+	if err != nil {
+		log.Printf("rpc error while restarting program: %s", err.Error())
+		view.showNotification(err.Error(), true)
+		return
+	}
+	view.SetBlocking(false)
+	view.pageView.testsPage.SetFilter(cmd.Filter)
+	view.showNotification(fmt.Sprintf("Restarted with %s", strings.Join(flags, " ")), false)
+}
+
+// Fetch the current state of the target, e.g. when opening a core dump.
+type LoadState struct {
+}
+
+func (cmd *LoadState) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	state, err := client.GetState()
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		view.showNotification(err.Error(), true)
+		return
+	}
+	if state.CurrentThread == nil {
+		return
+	}
+	debuggerMoveCommand(view, app, client, state)
+
+	lg := ListGoroutines{}
+	lg.run(view, app, client)
+}
+
+type SearchOutput struct {
+	Text string
+}
+
+func (cmd *SearchOutput) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	view.outputSearchChan <- cmd.Text
+}
+
+type SendInput struct {
+	Text string
+}
+
+func (cmd *SendInput) run(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	if view.programInput == nil {
+		view.showNotification("Program is not attached to a pseudo-terminal, start dlvtui with -pty to send input.", true)
+		return
+	}
+	if _, err := io.WriteString(view.programInput, cmd.Text+"\n"); err != nil {
+		log.Printf("Error writing program input: %s", err.Error())
+		view.showNotification(err.Error(), true)
+	}
+}
+
+// Connect to the backend, starting it first if Launch is set.
+// Breakpoints that are known to the client are recreated if the backend is started again.
+type Connect struct {
+	Launch bool
+	Init   []LineCommand // Commands run once connected.
+}
+
+func (cmd *Connect) run(view *View, app *tview.Application, _ *rpc2.RPCClient) {
+	view.cmdHandler.SetClient(nil)
+
+	if cmd.Launch {
+		if view.backend.IsRemote() {
+			view.showNotification("The dlv backend was not started by dlvtui and can't be relaunched.", true)
+			return
+		}
+		if err := view.backend.Start(); err != nil {
+			view.onBackendLost(err.Error())
+			return
+		}
+	}
+	client, err := view.backend.Connect()
+	if err != nil {
+		view.onBackendLost(err.Error())
+		return
+	}
+
+	files, err := client.ListSources("")
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		view.onBackendLost(err.Error())
+		return
+	}
+	if len(files) == 0 {
+		log.Printf("Error: empty source list.")
+	}
+	for i := range files {
+		files[i] = toLocalPath(files[i])
+	}
+	view.navState.SourceFiles = files
+
+	fns, err := client.ListFunctions("")
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+	}
+	sort.Strings(fns)
+	view.navState.Functions = fns
+	restore := view.navState.ProjectPath == ""
+	if restore {
+		view.navState.ProjectPath = resolveProjectDir(client)
+		log.Printf("Using dir: %s", view.navState.ProjectPath)
+	}
+
+	client.SetReturnValuesLoadConfig(&gConfig.LoadConfig)
+	// A relaunched backend has none of the breakpoints, a server that was
+	// reconnected to may have been restarted and lost some of them.
+	existing := []*api.Breakpoint{}
+	if !cmd.Launch {
+		if existing, err = client.ListBreakpoints(true); err != nil {
+			log.Printf("rpc error: %s", err.Error())
+		}
+	}
+	for _, wp := range view.navState.Watchpoints {
+		if !hasBreakpointID(existing, wp.ID) {
+			removed := *wp
+			removed.Removed = true
+			view.watchpointChan <- &removed
+		}
+	}
+	for _, bp := range view.navState.GetAllBreakpoints() {
+		if bp.ID < 0 || bp.Disabled || hasBreakpointAt(existing, toBackendPath(bp.File), bp.Line) {
+			continue
+		}
+		recreateBreakpoint(bp).run(view, app, client)
+	}
+	if !view.readOnly {
+		createCatchpoints(view, client)
+	}
+	// Breakpoints can't be created in a core dump.
+	if restore && !view.readOnly {
+		restoreSession(view, app, client)
+	}
+
+	view.cmdHandler.SetClient(client)
+	view.SetBlocking(false)
+
+	commands := []LineCommand{&GetBreakpoints{}}
+	commands = append(commands, view.onConnect...)
+	commands = append(commands, cmd.Init...)
+	for _, c := range commands {
+		view.cmdHandler.RunCommand(c)
+	}
+}
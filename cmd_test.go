@@ -0,0 +1,28 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-delve/delve/service/api"
+)
+
+func TestParseWatchCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want LineCommand
+	}{
+		{[]string{"x"}, &CreateWatchpoint{Expr: "x", Type: api.WatchWrite}},
+		{[]string{"-w", "s.n"}, &CreateWatchpoint{Expr: "s.n", Type: api.WatchWrite}},
+		{[]string{"-r", "x"}, &CreateWatchpoint{Expr: "x", Type: api.WatchRead}},
+		{[]string{"-rw", "a[0]"}, &CreateWatchpoint{Expr: "a[0]", Type: api.WatchRead | api.WatchWrite}},
+		{[]string{"*p", "+", "1"}, &CreateWatchpoint{Expr: "*p + 1", Type: api.WatchWrite}},
+		{[]string{}, nil},
+		{[]string{"-r"}, nil},
+	}
+	for _, tt := range tests {
+		if got := parseWatchCommand(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseWatchCommand(%q) = %#v, want %#v", tt.args, got, tt.want)
+		}
+	}
+}
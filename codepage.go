@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/ilmari-h/dlvtui/nav"
 	"fmt"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -121,10 +122,7 @@ func (page *CodePage) HandleKeyEvent(event *tcell.EventKey) *tcell.EventKey {
 		if len(bps[page.navState.CurrentFile.Path]) != 0 { // Using 1 based indices on the backend.
 			if bp, ok := bps[page.navState.CurrentFile.Path][page.navState.CurrentLine()+1]; ok {
 				if bp.Disabled {
-					page.commandHandler.RunCommand(&CreateBreakpoint{
-						Line: page.navState.CurrentLine() + 1, // Using 1 based indices on the backend.
-						File: page.navState.CurrentFile.Path,
-					})
+					page.commandHandler.RunCommand(recreateBreakpoint(bp))
 				} else {
 					page.commandHandler.RunCommand(&ClearBreakpoint{bp, true, nil})
 				}
@@ -132,6 +130,55 @@ func (page *CodePage) HandleKeyEvent(event *tcell.EventKey) *tcell.EventKey {
 		}
 		return nil
 	}
+	if keyPressed(event, gConfig.Keys.EditCondition) {
+		if page.navState.CurrentFile != nil {
+			cond := ""
+			if bp, ok := page.navState.Breakpoints[page.navState.CurrentFile.Path][page.navState.CurrentLine()+1]; ok {
+				cond = bp.Cond
+			}
+			page.commandHandler.view.toCmdModeWithText("cond " + cond)
+		}
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.RunToCursor) {
+		if page.navState.CurrentFile != nil {
+			page.commandHandler.RunCommand(&RunToLine{
+				Line: page.navState.CurrentLine() + 1, // Using 1 based indices on the backend.
+				File: page.navState.CurrentFile.Path,
+			})
+		}
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.EditActions) {
+		if page.navState.CurrentFile != nil {
+			actions := []string{}
+			if bp, ok := page.navState.Breakpoints[page.navState.CurrentFile.Path][page.navState.CurrentLine()+1]; ok {
+				actions = bp.Actions
+			}
+			page.commandHandler.view.toCmdModeWithText("actions " + strings.Join(actions, "; "))
+		}
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.EditLogMessage) {
+		if page.navState.CurrentFile != nil {
+			msg := ""
+			if bp, ok := page.navState.Breakpoints[page.navState.CurrentFile.Path][page.navState.CurrentLine()+1]; ok {
+				msg = bp.LogMessage
+			}
+			page.commandHandler.view.toCmdModeWithText("log " + msg)
+		}
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.EditHitCondition) {
+		if page.navState.CurrentFile != nil {
+			hitCond := ""
+			if bp, ok := page.navState.Breakpoints[page.navState.CurrentFile.Path][page.navState.CurrentLine()+1]; ok {
+				hitCond = bp.HitCond
+			}
+			page.commandHandler.view.toCmdModeWithText("hitcond " + hitCond)
+		}
+		return nil
+	}
 	if keyPressed(event, gConfig.Keys.ClearBreakpoint) {
 		bps := page.navState.Breakpoints
 		// If breakpoint on this line, remove it.
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/go-delve/delve/service/api"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -175,11 +176,22 @@ type Keys struct {
 
 	ToggleBreakpoint string
 	ClearBreakpoint  string
+	EditCondition    string
+	EditHitCondition string
+	EditLogMessage   string
+	Watch            string
+	EditActions      string
+	RunToCursor      string
+	LoadMore         string
+
+	NextMatch string
+	PrevMatch string
 }
 
 type Colors struct {
 	BpFg           int
 	BpActiveFg     int
+	LogpointFg     int
 	LineFg         int
 	LineSelectedFg int
 	LineSelectedBg int
@@ -206,6 +218,10 @@ type Colors struct {
 	MenuFg         int
 	MenuSelectedBg int
 	MenuSelectedFg int
+
+	OutputFg       int
+	OutputStderrFg int
+	OutputMarkerFg int
 }
 
 type Icons struct {
@@ -213,6 +229,10 @@ type Icons struct {
 	BpDisabled string
 	BpActive   string
 
+	Logpoint         string
+	LogpointDisabled string
+	Watchpoint       string
+
 	IndRunning     string
 	IndStopped     string
 	IndExitSuccess string
@@ -221,9 +241,16 @@ type Icons struct {
 
 type Config struct {
 	SyntaxHighlighter string
+	OutputLines       int // Number of lines of program output kept in the output page.
+	SubstitutePath    []PathRule
 	Keys              Keys
 	Colors            Colors
 	Icons             Icons
+
+	BreakOnRecoveredPanics bool // Stop on every panic, not only unrecovered ones.
+	BreakOnExit            bool // Stop on calls to os.Exit, including the ones by log.Fatal.
+
+	LoadConfig api.LoadConfig // Limits for loading the values of variables.
 }
 
 var gConfig Config
@@ -242,10 +269,20 @@ func NewConfig() Config {
 		SelectItem:       "Enter",
 		ToggleBreakpoint: "d",
 		ClearBreakpoint:  "D",
+		EditCondition:    "c",
+		EditHitCondition: "C",
+		EditLogMessage:   "L",
+		Watch:            "w",
+		EditActions:      "A",
+		RunToCursor:      "r",
+		LoadMore:         "m",
+		NextMatch:        "n",
+		PrevMatch:        "N",
 	}
 	colorconf := Colors{
 		BpFg:           9,
 		BpActiveFg:     1,
+		LogpointFg:     3,
 		LineFg:         15,
 		LineSelectedFg: 0,
 		LineSelectedBg: 15,
@@ -272,12 +309,20 @@ func NewConfig() Config {
 		MenuFg:         0,
 		MenuSelectedBg: 15,
 		MenuSelectedFg: 0,
+
+		OutputFg:       15,
+		OutputStderrFg: 9,
+		OutputMarkerFg: 8,
 	}
 	iconconf := Icons{
 		Bp:         "●",
 		BpDisabled: "○",
 		BpActive:   "◎",
 
+		Logpoint:         "◆",
+		LogpointDisabled: "◇",
+		Watchpoint:       "◉",
+
 		IndRunning:     "▶",
 		IndStopped:     "◼",
 		IndExitSuccess: "⚑",
@@ -285,9 +330,22 @@ func NewConfig() Config {
 	}
 	return Config{
 		SyntaxHighlighter: "",
+		OutputLines:       10000,
+		SubstitutePath:    []PathRule{},
 		Keys:              keyconf,
 		Colors:            colorconf,
 		Icons:             iconconf,
+
+		BreakOnRecoveredPanics: false,
+		BreakOnExit:            false,
+
+		LoadConfig: api.LoadConfig{
+			FollowPointers:     true,
+			MaxVariableRecurse: 5,
+			MaxStringLen:       999,
+			MaxArrayValues:     999,
+			MaxStructFields:    -1,
+		},
 	}
 }
 
@@ -351,3 +409,54 @@ func getConfig() {
 		log.Fatalf("Error reading icon configuration: %v", conf_icons_err)
 	}
 }
+
+// Named launch configuration defined in a project file.
+type Profile struct {
+	Mode           string // One of exec, debug, test, attach, core or connect.
+	Target         string // Executable, package, PID or address depending on mode.
+	Core           string
+	Args           []string
+	Env            []string
+	Wd             string
+	Stdin          string
+	Pty            bool
+	Root           string
+	SubstitutePath []string // Rules in the form from:to.
+	BuildFlags     string
+	Tags           string
+	Run            string
+	Breakpoints    []string // Locations such as main.go:42 or pkg.Function.
+}
+
+var profileModes = []string{"exec", "debug", "test", "attach", "core", "connect"}
+
+// Read launch profile with the given name from .dlvtui.yaml in the working directory.
+func getProfile(name string) (Profile, error) {
+	v := viper.New()
+	v.SetConfigName(".dlvtui")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(".")
+
+	if err := v.ReadInConfig(); err != nil {
+		return Profile{}, err
+	}
+
+	profiles := make(map[string]Profile)
+	if err := v.UnmarshalKey("profiles", &profiles); err != nil {
+		return Profile{}, err
+	}
+	profile, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		return Profile{}, fmt.Errorf("no profile named %s in %s", name, v.ConfigFileUsed())
+	}
+	if profile.Mode == "" {
+		profile.Mode = "exec"
+	}
+	for _, mode := range profileModes {
+		if profile.Mode == mode {
+			return profile, nil
+		}
+	}
+	return Profile{}, fmt.Errorf("invalid mode %s in profile %s, expected one of %s",
+		profile.Mode, name, strings.Join(profileModes, ", "))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
@@ -50,16 +51,17 @@ func (page *GoroutinePage) RenderGoroutines(grs []*api.Goroutine, currId int) {
 	// Filter out external goroutines.
 	projectGrs := []*api.Goroutine{}
 	for _, gor := range grs {
-		if strings.HasPrefix(gor.CurrentLoc.File, page.commandHandler.view.navState.ProjectPath) ||
-			strings.HasPrefix(gor.GoStatementLoc.File, page.commandHandler.view.navState.ProjectPath) ||
-			strings.HasPrefix(gor.StartLoc.File, page.commandHandler.view.navState.ProjectPath) {
+		navState := page.commandHandler.view.navState
+		if navState.InProject(gor.CurrentLoc.File) ||
+			navState.InProject(gor.GoStatementLoc.File) ||
+			navState.InProject(gor.StartLoc.File) {
 			projectGrs = append(projectGrs, gor)
 		}
 	}
 
 	page.listView.Clear()
 	selectedI := 0
-	page.renderedGoroutines = grs
+	page.renderedGoroutines = projectGrs
 	for i, gor := range projectGrs {
 		label := fmt.Sprintf("  [%s]%d.[%s] %s[%s]:%d",
 			iToColorS(gConfig.Colors.VarTypeFg),
@@ -69,6 +71,9 @@ func (page *GoroutinePage) RenderGoroutines(grs []*api.Goroutine, currId int) {
 			iToColorS(gConfig.Colors.VarValueFg),
 			gor.CurrentLoc.Line,
 		)
+		if len(gor.Labels) > 0 {
+			label += fmt.Sprintf(" [%s]%s", iToColorS(gConfig.Colors.VarAddrFg), tview.Escape(labelsText(gor.Labels)))
+		}
 		if gor.ID == currId {
 			selectedI = i
 			label = fmt.Sprintf("> [%s::b]%d. [%s]%s[%s]:%d",
@@ -94,6 +99,31 @@ func (page *GoroutinePage) RenderGoroutines(grs []*api.Goroutine, currId int) {
 	page.listView.SetCurrentItem(selectedI)
 }
 
+// Labels of a goroutine sorted by key, such as "region=eu worker=7".
+func labelsText(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+// Start typing a command that creates a breakpoint on the current line of the
+// code page, restricted to the selected goroutine.
+func (page *GoroutinePage) breakInSelected() {
+	navState := page.commandHandler.view.navState
+	i := page.listView.GetCurrentItem()
+	if navState.CurrentFile == nil || i < 0 || i >= len(page.renderedGoroutines) {
+		return
+	}
+	page.commandHandler.view.toCmdModeWithText(fmt.Sprintf("break %s:%d if goroutine %d",
+		navState.CurrentFile.Path,
+		navState.CurrentLine()+1, // Using 1 based indices on the backend.
+		page.renderedGoroutines[i].ID,
+	))
+}
+
 func (sp *GoroutinePage) GetWidget() tview.Primitive {
 	return sp.widget
 }
@@ -103,6 +133,10 @@ func (sp *GoroutinePage) GetName() string {
 }
 
 func (sp *GoroutinePage) HandleKeyEvent(event *tcell.EventKey) *tcell.EventKey {
+	if keyPressed(event, gConfig.Keys.Breakpoint) {
+		sp.breakInSelected()
+		return nil
+	}
 	if keyPressed(event, gConfig.Keys.LineDown) {
 		sp.listView.SetCurrentItem(sp.listView.GetCurrentItem() + 1)
 		return nil
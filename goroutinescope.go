@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-delve/delve/service/api"
+	"github.com/go-delve/delve/service/rpc2"
+	"github.com/ilmari-h/dlvtui/nav"
+	log "github.com/sirupsen/logrus"
+)
+
+// Breakpoints can be restricted to a goroutine or to goroutines with a pprof
+// label. Goroutine IDs are checked by the condition of the breakpoint. Labels
+// can't be, since a missing key in the label map is an error that stops the
+// program, so the condition only checks that the goroutine has labels and the
+// client continues past the ones without the label.
+const hasLabelsCond = "runtime.curg.labels != nil"
+
+// Condition of a breakpoint as it's sent to the backend.
+func scopedCond(cond string, goroutineID int, label string) string {
+	scope := ""
+	if goroutineID > 0 {
+		scope = fmt.Sprintf("runtime.curg.goid == %d", goroutineID)
+	} else if label != "" {
+		scope = hasLabelsCond
+	}
+	if scope == "" {
+		return cond
+	}
+	if cond == "" {
+		return scope
+	}
+	return fmt.Sprintf("%s && (%s)", scope, cond)
+}
+
+// Text shown for the goroutines a breakpoint is restricted to, empty if it
+// isn't.
+func goroutineScopeText(bp *nav.UiBreakpoint) string {
+	if bp.GoroutineID > 0 {
+		return fmt.Sprintf("in goroutine %d", bp.GoroutineID)
+	}
+	if bp.Label != "" {
+		return fmt.Sprintf("in goroutines labeled %s", bp.Label)
+	}
+	return ""
+}
+
+// Parse "<location> [if <condition>]" of :break. The condition can start with
+// "goroutine <id>", which restricts the breakpoint to a goroutine, or with
+// "label <key>=<value>", which restricts it to goroutines with a pprof label,
+// followed by "&& <condition>". A malformed scope is reported to the user.
+func parseBreakCommand(args []string, temporary bool) LineCommand {
+	cmd, err := parseBreakArgs(args, temporary)
+	if err != nil {
+		return &Notify{Message: err.Error(), Error: true}
+	}
+	if cmd == nil {
+		return nil
+	}
+	return cmd
+}
+
+func parseBreakArgs(args []string, temporary bool) (*CreateBreakpointAt, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	loc, cond, _ := strings.Cut(strings.Join(args, " "), " if ")
+	cmd := &CreateBreakpointAt{
+		Location:  strings.TrimSpace(loc),
+		Cond:      strings.TrimSpace(cond),
+		Temporary: temporary,
+	}
+	if arg, rest, ok := cutScope(cmd.Cond, "goroutine"); ok {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("Invalid goroutine %q, expected goroutine <id>.", arg)
+		}
+		cmd.GoroutineID, cmd.Cond = id, rest
+	} else if arg, rest, ok := cutScope(cmd.Cond, "label"); ok {
+		if !strings.Contains(arg, "=") {
+			return nil, fmt.Errorf("Invalid label %q, expected label <key>=<value>.", arg)
+		}
+		cmd.Label, cmd.Cond = arg, rest
+	}
+	return cmd, nil
+}
+
+// Cut "<keyword> <arg>" from the start of a condition. The rest of the
+// condition must be empty or start with "&&".
+func cutScope(cond string, keyword string) (arg string, rest string, ok bool) {
+	fields := strings.Fields(cond)
+	if len(fields) < 2 || fields[0] != keyword {
+		return "", "", false
+	}
+	rest = strings.TrimSpace(strings.TrimPrefix(cond, keyword))
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, fields[1]))
+	if rest == "" {
+		return fields[1], "", true
+	}
+	if !strings.HasPrefix(rest, "&&") {
+		return "", "", false
+	}
+	return fields[1], strings.TrimSpace(strings.TrimPrefix(rest, "&&")), true
+}
+
+// Whether the program stopped on a breakpoint restricted to a label in a
+// goroutine that doesn't have it.
+func outsideLabelScope(view *View, client *rpc2.RPCClient, state *api.DebuggerState) bool {
+	if state == nil || state.Exited || state.CurrentThread == nil || state.CurrentThread.Breakpoint == nil {
+		return false
+	}
+	th := state.CurrentThread
+	bp, ok := view.navState.Breakpoints[toLocalPath(th.Breakpoint.File)][th.Breakpoint.Line]
+	if !ok || bp.Label == "" {
+		return false
+	}
+	filter := []api.ListGoroutinesFilter{{Kind: api.GoroutineLabel, Arg: bp.Label}}
+	gs, _, _, _, err := client.ListGoroutinesWithFilter(0, 0, filter, nil)
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+		return false
+	}
+	for _, g := range gs {
+		if g.ID == th.GoroutineID {
+			return false
+		}
+	}
+	return true
+}
+
+// Continue past stops on breakpoints restricted to a label in goroutines that
+// don't have it. A step that was interrupted by such a stop is resumed by
+// continuing.
+func skipOutsideLabelScope(view *View, client *rpc2.RPCClient, state *api.DebuggerState) *api.DebuggerState {
+	for outsideLabelScope(view, client, state) {
+		state = continueTracing(view, client)
+	}
+	return state
+}
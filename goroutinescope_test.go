@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCutScope(t *testing.T) {
+	tests := []struct {
+		cond, keyword string
+		arg, rest     string
+		ok            bool
+	}{
+		{"goroutine 5", "goroutine", "5", "", true},
+		{"goroutine 5 && x > 3", "goroutine", "5", "x > 3", true},
+		{"  goroutine   5&&x", "goroutine", "", "", false},
+		{"label k=v && n == 2", "label", "k=v", "n == 2", true},
+		{"goroutine > 5", "goroutine", "", "", false},
+		{"goroutine 5 || x", "goroutine", "", "", false},
+		{"goroutine", "goroutine", "", "", false},
+		{"x > 3", "goroutine", "", "", false},
+		{"", "label", "", "", false},
+	}
+	for _, tt := range tests {
+		arg, rest, ok := cutScope(tt.cond, tt.keyword)
+		if arg != tt.arg || rest != tt.rest || ok != tt.ok {
+			t.Errorf("cutScope(%q, %q) = %q, %q, %v, want %q, %q, %v",
+				tt.cond, tt.keyword, arg, rest, ok, tt.arg, tt.rest, tt.ok)
+		}
+	}
+}
+
+func TestParseBreakCommand(t *testing.T) {
+	tests := []struct {
+		args      []string
+		temporary bool
+		want      LineCommand
+	}{
+		{[]string{"main.go:42"}, false, &CreateBreakpointAt{Location: "main.go:42"}},
+		{[]string{"main.main"}, true, &CreateBreakpointAt{Location: "main.main", Temporary: true}},
+		{
+			[]string{"main.go:42", "if", "i", "==", "100"}, false,
+			&CreateBreakpointAt{Location: "main.go:42", Cond: "i == 100"},
+		},
+		{
+			[]string{"main.go:42", "if", "goroutine", "5"}, false,
+			&CreateBreakpointAt{Location: "main.go:42", GoroutineID: 5},
+		},
+		{
+			[]string{"main.go:42", "if", "goroutine", "5", "&&", "x", ">", "3"}, false,
+			&CreateBreakpointAt{Location: "main.go:42", GoroutineID: 5, Cond: "x > 3"},
+		},
+		{
+			[]string{"pkg.Handle", "if", "label", "request=42", "&&", "err", "!=", "nil"}, false,
+			&CreateBreakpointAt{Location: "pkg.Handle", Label: "request=42", Cond: "err != nil"},
+		},
+		{
+			[]string{"main.go:42", "if", "goroutine", ">", "5"}, false,
+			&CreateBreakpointAt{Location: "main.go:42", Cond: "goroutine > 5"},
+		},
+		{
+			[]string{"main.go:42", "if", "goroutine", "abc"}, false,
+			&Notify{Message: `Invalid goroutine "abc", expected goroutine <id>.`, Error: true},
+		},
+		{
+			[]string{"main.go:42", "if", "goroutine", "0"}, false,
+			&Notify{Message: `Invalid goroutine "0", expected goroutine <id>.`, Error: true},
+		},
+		{
+			[]string{"main.go:42", "if", "label", "request"}, false,
+			&Notify{Message: `Invalid label "request", expected label <key>=<value>.`, Error: true},
+		},
+		{[]string{}, false, nil},
+	}
+	for _, tt := range tests {
+		if got := parseBreakCommand(tt.args, tt.temporary); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseBreakCommand(%q, %v) = %#v, want %#v", tt.args, tt.temporary, got, tt.want)
+		}
+	}
+}
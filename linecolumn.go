@@ -42,7 +42,16 @@ func (lc *LineColumn) Render(lineStart, lineEnd, current int) {
 	for i := lineStart; i <= lineEnd; i++ {
 		bp := " "
 		if fbp, ok := breakpoints[i]; ok && fbp.ID >= 0 {
-			if breakpoints[i].Disabled {
+			if fbp.Tracepoint {
+				icon := gConfig.Icons.Logpoint
+				if fbp.Disabled {
+					icon = gConfig.Icons.LogpointDisabled
+				}
+				bp = fmt.Sprintf("[%s]%s[-::-]",
+					iToColorS(gConfig.Colors.LogpointFg),
+					icon,
+				)
+			} else if breakpoints[i].Disabled {
 				bp = fmt.Sprintf("[%s]%s[-::-]",
 					iToColorS(gConfig.Colors.BpFg),
 					gConfig.Icons.BpDisabled,
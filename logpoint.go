@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-delve/delve/service/api"
+)
+
+// Split a logpoint message such as "user={u.ID} n={len(items)}" into the text
+// between expressions and the expressions in braces.
+func parseLogMessage(msg string) (text []string, exprs []string, err error) {
+	depth := 0
+	start := 0
+	for i, c := range msg {
+		switch c {
+		case '{':
+			if depth == 0 {
+				text = append(text, msg[start:i])
+				start = i + 1
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				return nil, nil, fmt.Errorf("unmatched } at %d in log message", i)
+			}
+			depth--
+			if depth == 0 {
+				expr := strings.TrimSpace(msg[start:i])
+				if expr == "" {
+					return nil, nil, fmt.Errorf("empty expression at %d in log message", start)
+				}
+				exprs = append(exprs, expr)
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, nil, fmt.Errorf("unmatched { at %d in log message", start-1)
+	}
+	text = append(text, msg[start:])
+	return text, exprs, nil
+}
+
+// Make bp a tracepoint that evaluates the expressions of msg without stopping.
+// An empty msg makes it a regular breakpoint again.
+func setLogMessage(bp *api.Breakpoint, msg string) error {
+	if msg == "" {
+		bp.Tracepoint = false
+		bp.Variables = nil
+		bp.LoadArgs = &gConfig.LoadConfig
+		bp.LoadLocals = &gConfig.LoadConfig
+		return nil
+	}
+	_, exprs, err := parseLogMessage(msg)
+	if err != nil {
+		return err
+	}
+	bp.Tracepoint = true
+	bp.Variables = exprs
+	bp.LoadArgs = nil
+	bp.LoadLocals = nil
+	return nil
+}
+
+// Interpolate the values of a tracepoint hit into its log message. Without a
+// message, the evaluated expressions are listed.
+func formatLogMessage(msg string, info *api.BreakpointInfo) string {
+	var vars []api.Variable
+	if info != nil {
+		vars = info.Variables
+	}
+	text, exprs, err := parseLogMessage(msg)
+	if msg == "" || err != nil {
+		values := make([]string, len(vars))
+		for i := range vars {
+			values[i] = fmt.Sprintf("%s = %s", vars[i].Name, vars[i].SinglelineString())
+		}
+		return strings.Join(values, ", ")
+	}
+
+	var sb strings.Builder
+	for i := range exprs {
+		sb.WriteString(text[i])
+		if i < len(vars) {
+			sb.WriteString(vars[i].SinglelineString())
+		} else {
+			sb.WriteString("?")
+		}
+	}
+	sb.WriteString(text[len(text)-1])
+	return sb.String()
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLogMessage(t *testing.T) {
+	tests := []struct {
+		msg       string
+		wantText  []string
+		wantExprs []string
+		wantErr   bool
+	}{
+		{"hello", []string{"hello"}, nil, false},
+		{"", []string{""}, nil, false},
+		{"user={u.ID} n={len(items)}", []string{"user=", " n=", ""}, []string{"u.ID", "len(items)"}, false},
+		{"{x}", []string{"", ""}, []string{"x"}, false},
+		{"v={ m[struct{}{}] }", []string{"v=", ""}, []string{"m[struct{}{}]"}, false},
+		{"x={}", nil, nil, true},
+		{"x={y", nil, nil, true},
+		{"x=y}", nil, nil, true},
+	}
+	for _, tt := range tests {
+		text, exprs, err := parseLogMessage(tt.msg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseLogMessage(%q) error = %v, want error %v", tt.msg, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(text, tt.wantText) || !reflect.DeepEqual(exprs, tt.wantExprs) {
+			t.Errorf("parseLogMessage(%q) = %q, %q, want %q, %q", tt.msg, text, exprs, tt.wantText, tt.wantExprs)
+		}
+	}
+}
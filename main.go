@@ -1,23 +1,53 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"syscall"
 
 	"github.com/ilmari-h/dlvtui/nav"
 
-	"github.com/go-delve/delve/service/rpc2"
 	"github.com/rivo/tview"
 	log "github.com/sirupsen/logrus"
 )
 
-func execDebuggerCmd(executable string, exArgs []string, port string) []string {
+// Options passed on to the debugged program.
+type ProgramOptions struct {
+	Args []string // Arguments of the program.
+	Env  []string // Additional environment variables in the form KEY=VALUE.
+	Wd   string   // Working directory of the program.
+
+	Redirects [3]string // Files stdin, stdout and stderr are redirected to.
+	Tty       string    // Terminal used for all input and output of the program.
+}
+
+var redirectNames = [3]string{"stdin", "stdout", "stderr"}
+
+// Append working directory flag, target and program arguments to dlv arguments.
+func appendProgramArgs(allArgs []string, target string, opts ProgramOptions) []string {
+	if opts.Wd != "" {
+		allArgs = append(allArgs, "--wd="+opts.Wd)
+	}
+	if opts.Tty != "" {
+		allArgs = append(allArgs, "--tty="+opts.Tty)
+	}
+	for i, r := range opts.Redirects {
+		if r != "" {
+			allArgs = append(allArgs, "--redirect="+redirectNames[i]+":"+r)
+		}
+	}
+	allArgs = append(allArgs, target)
+	if len(opts.Args) > 0 {
+		allArgs = append(allArgs, "--")
+		allArgs = append(allArgs, opts.Args...)
+	}
+	return allArgs
+}
+
+func execDebuggerCmd(executable string, opts ProgramOptions, port string) []string {
 	log.Printf("Debugging executable at path: %s", executable)
 	allArgs := []string{
 		"exec",
@@ -25,13 +55,8 @@ func execDebuggerCmd(executable string, exArgs []string, port string) []string {
 		"--accept-multiclient",
 		"--api-version=2",
 		"--listen=127.0.0.1:" + port,
-		executable,
 	}
-	if exArgs != nil && len(exArgs) > 0 {
-		allArgs = append(allArgs, "--")
-		allArgs = append(allArgs, exArgs...)
-	}
-	return allArgs
+	return appendProgramArgs(allArgs, executable, opts)
 }
 
 func attachDebuggerCmd(pid string, exArgs []string, port string) []string {
@@ -51,76 +76,199 @@ func attachDebuggerCmd(pid string, exArgs []string, port string) []string {
 	return allArgs
 }
 
-func startDebugger(commandArgs []string) int {
-	log.Printf("Starting dlv-backend: dlv %s", strings.Join(commandArgs, " "))
-	cmd := exec.Command(
-		"dlv",
-		commandArgs...,
-	)
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Pdeathsig: syscall.SIGKILL,
-	}
-	stdout, _ := cmd.StdoutPipe()
-	if err := cmd.Start(); err != nil {
-		log.Printf("Error starting dlv-backend: %s", string(err.Error()))
-		panic(err)
+func debugDebuggerCmd(pkg string, buildFlags string, output string, opts ProgramOptions, port string) []string {
+	log.Printf("Building and debugging package: %s", pkg)
+	allArgs := []string{
+		"debug",
+		"--headless",
+		"--accept-multiclient",
+		"--api-version=2",
+		"--listen=127.0.0.1:" + port,
+		"--output=" + output,
 	}
+	if buildFlags != "" {
+		allArgs = append(allArgs, "--build-flags="+buildFlags)
+	}
+	return appendProgramArgs(allArgs, pkg, opts)
+}
 
-	log.Printf("dlv-backend running with pid %d", cmd.Process.Pid)
-
-	go func() {
-		in := bufio.NewScanner(stdout)
-		for in.Scan() {
-			log.Printf("dlv-backend:%s", in.Text())
-		}
-		if err := in.Err(); err != nil {
-			log.Printf("Error: %s", err)
-		}
-	}()
+func testDebuggerCmd(pkg string, buildFlags string, output string, opts ProgramOptions, port string) []string {
+	log.Printf("Building and debugging tests of package: %s", pkg)
+	allArgs := []string{
+		"test",
+		"--headless",
+		"--accept-multiclient",
+		"--api-version=2",
+		"--listen=127.0.0.1:" + port,
+		"--output=" + output,
+	}
+	if buildFlags != "" {
+		allArgs = append(allArgs, "--build-flags="+buildFlags)
+	}
+	return appendProgramArgs(allArgs, pkg, opts)
+}
 
-	return cmd.Process.Pid
+// Combine user provided build flags and tags into a single --build-flags value.
+func getBuildFlags(flags string, tags string) string {
+	if tags == "" {
+		return flags
+	}
+	return strings.TrimSpace(flags + " -tags=" + tags)
 }
 
-// Used for autosuggestions for now, a browser window in the future.
-func getFileList(client *rpc2.RPCClient) chan []string {
-	filesListC := make(chan []string)
-	go func() {
-		files, err := client.ListSources("")
-		if err != nil {
-			log.Fatalf("Error tracing directory: %s", err)
-		}
-		filesListC <- files
-	}()
-	return filesListC
+func coreDebuggerCmd(executable string, core string, port string) []string {
+	log.Printf("Debugging core dump %s of executable %s", core, executable)
+	return []string{
+		"core",
+		"--headless",
+		"--accept-multiclient",
+		"--api-version=2",
+		"--listen=127.0.0.1:" + port,
+		executable,
+		core,
+	}
 }
 
 var (
-	port       string
-	logfile    string
-	attachMode bool
+	port        string
+	logfile     string
+	attachMode  bool
+	debugMode   bool
+	buildFlags  string
+	buildTags   string
+	testMode    bool
+	testRun     string
+	coreFile    string
+	connect     string
+	target      string
+	progOpts    ProgramOptions
+	profile     string
+	initialBps  []string
+	usePty      bool
+	projectRoot string
+	substitute  []string
 )
 
-func init() {
+// Flag that can be given multiple times.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// Apply launch profile, then parse flags again so that they take precedence.
+func loadProfile(exFlags *flag.FlagSet) {
+	p, err := getProfile(profile)
+	if err != nil {
+		fmt.Printf("Error loading profile: %s\n", err)
+		os.Exit(1)
+	}
+
+	args := progOpts.Args
+	switch p.Mode {
+	case "attach":
+		attachMode = true
+	case "debug":
+		debugMode = true
+	case "test":
+		testMode = true
+	case "core":
+		coreFile = p.Core
+	case "connect":
+		connect = p.Target
+	}
+	if p.Mode != "connect" && target == "" {
+		target = p.Target
+	}
+	testRun = p.Run
+	buildFlags = p.BuildFlags
+	buildTags = p.Tags
+	progOpts = ProgramOptions{Args: p.Args, Env: p.Env, Wd: p.Wd}
+	progOpts.Redirects[0] = p.Stdin
+	usePty = p.Pty
+	projectRoot = p.Root
+	substitute = nil
+	initialBps = p.Breakpoints
 
-	// Parse flags after first argument.
+	if len(os.Args) >= 2 && strings.HasPrefix(os.Args[1], "-") {
+		exFlags.Parse(os.Args[1:])
+	} else {
+		exFlags.Parse(os.Args[2:])
+	}
+	if len(args) > 0 {
+		progOpts.Args = args
+	}
+	// Rules are applied in order, so the ones given as flags come first.
+	substitute = append(substitute, p.SubstitutePath...)
+}
+
+// Parse flags after the first argument and open the log file. Done in main
+// rather than in init, so that the package can be tested.
+func parseFlags() {
 	exFlags := flag.NewFlagSet("", flag.ExitOnError)
-	exFlags.StringVar(&port, "port", "8181", "The port dlv rpc server will listen to.")
+	exFlags.StringVar(&port, "port", "0", "The port dlv rpc server will listen to. By default a free port is chosen.")
 	exFlags.BoolVar(&attachMode, "attach", false, "If enabled, attach debugger to process. Interpret first argument as PID.")
+	exFlags.BoolVar(&debugMode, "debug", false, "If enabled, build and debug a package. Interpret first argument as a package path.")
+	exFlags.BoolVar(&testMode, "test", false, "If enabled, build and debug the tests of a package. Interpret first argument as a package path.")
+	exFlags.StringVar(&testRun, "run", "", "Regular expression selecting the tests to run in test mode.")
+	exFlags.StringVar(&coreFile, "core", "", "Path to a core dump. If set, interpret first argument as the executable that produced it.")
+	exFlags.StringVar(&connect, "connect", "", "Address of an already running headless dlv server. If set, no debug target is needed.")
+	exFlags.StringVar(&buildFlags, "build-flags", "", "Build flags passed to the compiler in debug and test mode.")
+	exFlags.StringVar(&buildTags, "tags", "", "Comma separated list of build tags used in debug and test mode.")
+	exFlags.Var((*stringList)(&progOpts.Env), "env", "Environment variable KEY=VALUE of the debugged program. Can be given multiple times.")
+	exFlags.StringVar(&progOpts.Wd, "wd", "", "Working directory of the debugged program.")
+	exFlags.StringVar(&progOpts.Redirects[0], "stdin", "", "Path to a file the standard input of the debugged program is read from.")
+	exFlags.BoolVar(&usePty, "pty", false, "If enabled, attach the debugged program to a pseudo-terminal. Input can be sent to it with the send command.")
+	exFlags.StringVar(&projectRoot, "root", "", "Project root directory. By default the root of the module or workspace of the debugged program.")
+	exFlags.Var((*stringList)(&substitute), "substitute-path", "Rule from:to mapping source paths reported by dlv to local paths. Can be given multiple times.")
+	exFlags.StringVar(&profile, "profile", "", "Name of a launch profile defined in .dlvtui.yaml. Other flags override its values.")
 	exFlags.StringVar(&logfile, "logfile", "$XDG_DATA_HOME/dlvtui.log", "Path to the log file.")
 
-	if len(os.Args) < 2 {
+	// The target may be omitted when connecting to an existing server.
+	if len(os.Args) >= 2 && strings.HasPrefix(os.Args[1], "-") {
+		exFlags.Parse(os.Args[1:])
+	} else if len(os.Args) >= 2 {
+		target = os.Args[1]
+		exFlags.Parse(os.Args[2:])
+	}
+
+	// Arguments after the "--" separator are passed to the debugged program.
+	progOpts.Args = exFlags.Args()
+
+	if profile != "" {
+		loadProfile(exFlags)
+	}
+	for _, env := range progOpts.Env {
+		if !strings.Contains(env, "=") {
+			fmt.Printf("Invalid environment variable %s, expected KEY=VALUE.\n", env)
+			os.Exit(1)
+		}
+	}
+
+	if target == "" && connect == "" {
 		fmt.Println("No debug target provided.\n" +
-			"The first argument should be an executable or a PID if the flag `attach` is set.")
+			"The first argument should be an executable, a PID if the flag `attach` is set " +
+			"or a package if the flag `debug` or `test` is set.")
 		exFlags.Usage()
 		os.Exit(1)
 		return
 	}
-	exFlags.Parse(os.Args[2:])
 
 	log.SetLevel(log.InfoLevel)
 	file, err := os.OpenFile(os.ExpandEnv(logfile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	file.Truncate(0)
 	if err == nil {
+		// Every instance holds a shared lock on the log, it's cleared only if
+		// no other instance is writing to it.
+		fd := int(file.Fd())
+		if syscall.Flock(fd, syscall.LOCK_EX|syscall.LOCK_NB) == nil {
+			file.Truncate(0)
+		}
+		syscall.Flock(fd, syscall.LOCK_SH)
 		log.SetOutput(file)
 	} else {
 		log.Fatal("Failed to log to file: " + err.Error())
@@ -129,45 +277,99 @@ func init() {
 
 func main() {
 
+	parseFlags()
 	getConfig()
 
-	target := os.Args[1]
-
-	clientC := make(chan *rpc2.RPCClient)
-
-	if attachMode {
-		startDebugger(attachDebuggerCmd(target, []string{}, port))
-	} else {
-		targetFile, _ := filepath.Abs(target)
-		startDebugger(execDebuggerCmd(targetFile, []string{}, port))
+	// Rules given as flags take precedence over the ones in the configuration.
+	rules := []PathRule{}
+	for _, r := range substitute {
+		rule, err := parsePathRule(r)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		rules = append(rules, rule)
 	}
+	gConfig.SubstitutePath = append(rules, gConfig.SubstitutePath...)
 
-	go NewClient("127.0.0.1:"+port, clientC)
-	rpcClient := <-clientC
-	fileList := <-getFileList(rpcClient)
+	// Output of the target can only be captured when it's started by dlv.
+	var outputChan chan *OutputLine
+	var ptyMaster *os.File
+	if connect == "" && !attachMode && coreFile == "" {
+		outputChan = make(chan *OutputLine, 1024)
 
-	if fileList == nil || len(fileList) == 0 {
-		log.Fatalf("Error: empty source list.")
+		// dlv test doesn't support setting a terminal.
+		if usePty && !testMode {
+			master, closePty, err := attachPty(&progOpts, outputChan)
+			if err != nil {
+				log.Printf("Error opening pseudo-terminal: %s", err)
+			} else {
+				ptyMaster = master
+				defer closePty()
+			}
+		}
+		if ptyMaster == nil {
+			defer redirectOutput(&progOpts, outputChan)()
+		}
 	}
 
-	// Resolve dir. For now just find by assuming it's the one prefixed by /home.
-	var dir string
-	for _, f := range fileList {
-		if strings.HasPrefix(f, "/home/") && !strings.Contains(f, "/go/pkg") {
-			dir = filepath.Dir(f)
-			break
+	var backend *Backend
+	if connect != "" {
+		log.Printf("Connecting to dlv-backend at %s", connect)
+		backend = NewRemoteBackend(connect)
+	} else if attachMode {
+		backend = NewBackend(attachDebuggerCmd(target, []string{}, port), nil)
+	} else if coreFile != "" {
+		targetFile, _ := filepath.Abs(target)
+		core, _ := filepath.Abs(coreFile)
+		backend = NewBackend(coreDebuggerCmd(targetFile, core, port), nil)
+	} else if debugMode || testMode {
+		// Build into a temporary directory so the binary can be removed on exit.
+		buildDir, err := os.MkdirTemp("", "dlvtui")
+		if err != nil {
+			log.Fatalf("Error creating build directory: %s", err)
 		}
+		defer os.RemoveAll(buildDir)
+		flags := getBuildFlags(buildFlags, buildTags)
+		if testMode {
+			testOpts := progOpts
+			if testRun != "" {
+				testOpts.Args = append([]string{"-test.run", testRun}, progOpts.Args...)
+			}
+			backend = NewBackend(testDebuggerCmd(target, flags, filepath.Join(buildDir, "debug.test"), testOpts, port), progOpts.Env)
+		} else {
+			backend = NewBackend(debugDebuggerCmd(target, flags, filepath.Join(buildDir, "__debug_bin"), progOpts, port), progOpts.Env)
+		}
+	} else {
+		targetFile, _ := filepath.Abs(target)
+		backend = NewBackend(execDebuggerCmd(targetFile, progOpts, port), progOpts.Env)
 	}
-	log.Printf("Using dir: %s", dir)
+	defer backend.Kill()
 
 	app := tview.NewApplication()
-	nav := nav.NewNav(dir)
+	nav := nav.NewNav("")
 
-	nav.SourceFiles = fileList
-
-	CreateTui(app, &nav, rpcClient)
+	view := CreateTui(app, &nav, backend, outputChan)
+	view.remote = connect != ""
+	if ptyMaster != nil {
+		view.programInput = ptyMaster
+	}
+	if coreFile != "" {
+		view.readOnly = true
+		view.onConnect = append(view.onConnect, &LoadState{})
+	}
+	if testMode {
+		view.onConnect = append(view.onConnect, &ListTests{})
+	}
+	initCmds := []LineCommand{}
+	for _, loc := range initialBps {
+		initCmds = append(initCmds, &CreateBreakpointAt{Location: loc})
+	}
+	// A server given with -connect is already running.
+	view.cmdHandler.RunCommand(&Connect{Launch: connect == "", Init: initCmds})
 
 	if err := app.Run(); err != nil {
 		panic(err)
 	}
+	view.saveSession()
 }
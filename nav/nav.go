@@ -1,6 +1,13 @@
 package nav
 
 import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
 	"github.com/go-delve/delve/service/api"
 )
 
@@ -20,7 +27,22 @@ type DebuggerPos struct {
 }
 
 type UiBreakpoint struct {
-	Disabled bool
+	Disabled   bool
+	LogMessage string   // Message of a logpoint, with expressions in braces.
+	Group      string   // Regular expression the breakpoint was created from.
+	Actions    []string // Commands run when the breakpoint is hit.
+	Temporary  bool     // Removed the next time the program stops.
+
+	GoroutineID int    // Stops only in this goroutine if set.
+	Label       string // Stops only in goroutines with this pprof label, as key=value.
+	*api.Breakpoint
+}
+
+// Stops the program when the memory of an expression is accessed.
+type Watchpoint struct {
+	Removed     bool   // Cleared or gone out of scope.
+	GoroutineID int    // Goroutine the expression was evaluated in, -1 if unknown.
+	Function    string // Function of the stack frame the expression was evaluated in.
 	*api.Breakpoint
 }
 
@@ -53,6 +75,37 @@ func (nav *Nav) ChangeCurrentFile(file *File){
 	nav.CurrentFile = file
 }
 
+// Whether the file at path is inside the project directory. Every file is
+// while the project directory isn't known.
+func (nav *Nav) InProject(path string) bool {
+	return nav.ProjectPath == "" || strings.HasPrefix(path, nav.ProjectPath+"/")
+}
+
+// Update the state of known breakpoints as reported by the backend, such as
+// their hit counts. Breakpoints not created by the user are ignored.
+func (nav *Nav) UpdateBreakpoints(bps []*api.Breakpoint) {
+	for _, bp := range bps {
+		if _, ok := nav.Catchpoints[bp.Name]; ok && bp.Name != "" {
+			nav.Catchpoints[bp.Name] = bp
+			continue
+		}
+		if bp.WatchExpr != "" {
+			if wp, ok := nav.Watchpoints[bp.ID]; ok {
+				wp.Breakpoint = bp
+			}
+			continue
+		}
+		if uiBp, ok := nav.Breakpoints[bp.File][bp.Line]; ok && !uiBp.Disabled {
+			// The condition in the backend includes the goroutine scope.
+			cond := uiBp.Cond
+			uiBp.Breakpoint = bp
+			if uiBp.GoroutineID > 0 || uiBp.Label != "" {
+				uiBp.Cond = cond
+			}
+		}
+	}
+}
+
 func (nav *Nav) GetAllBreakpoints() []*UiBreakpoint {
 	bps := []*UiBreakpoint{}
 	if nav.Breakpoints == nil {
@@ -71,11 +124,14 @@ type Nav struct {
 
 	// Project level
 	SourceFiles []string
+	Functions   []string // Names of all functions in the program, sorted.
 	ProjectPath string
 	FileCache   map[string]*File
 	Goroutines []*api.Goroutine
 
 	Breakpoints map[string] map[int]*UiBreakpoint
+	Watchpoints map[int]*Watchpoint // By ID.
+	Catchpoints map[string]*api.Breakpoint // Breakpoints on panics and exits, by name.
 
 	CurrentFile *File
 	CurrentLines map[string]int
@@ -86,9 +142,88 @@ type Nav struct {
 	CurrentStackFrame *api.Stackframe
 }
 
-// Load saved session
-func loadNav(projectRootPath string) Nav {
-	return Nav{}
+// State of the UI saved per project between sessions.
+type Session struct {
+	Breakpoints  []SavedBreakpoint
+	CurrentLines map[string]int
+	Page         int
+}
+
+// Breakpoint as it's saved in sessions and exported files.
+type SavedBreakpoint struct {
+	File         string   `json:"file" yaml:"file"`
+	Line         int      `json:"line" yaml:"line"`
+	FunctionName string   `json:"function,omitempty" yaml:"function,omitempty"`
+	Disabled     bool     `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	Cond         string   `json:"cond,omitempty" yaml:"cond,omitempty"`
+	HitCond      string   `json:"hitCond,omitempty" yaml:"hitCond,omitempty"`
+	LogMessage   string   `json:"logMessage,omitempty" yaml:"logMessage,omitempty"`
+	Group        string   `json:"group,omitempty" yaml:"group,omitempty"`
+	Actions      []string `json:"actions,omitempty" yaml:"actions,omitempty"`
+	Label        string   `json:"label,omitempty" yaml:"label,omitempty"`
+}
+
+func (bp *UiBreakpoint) ToSaved() SavedBreakpoint {
+	return SavedBreakpoint{
+		File:         bp.File,
+		Line:         bp.Line,
+		FunctionName: bp.FunctionName,
+		Disabled:     bp.Disabled,
+		Cond:         bp.Cond,
+		HitCond:      bp.HitCond,
+		LogMessage:   bp.LogMessage,
+		Group:        bp.Group,
+		Actions:      bp.Actions,
+		Label:        bp.Label,
+	}
+}
+
+// Breakpoints created by the user, sorted by location.
+func (nav *Nav) SavedBreakpoints() []SavedBreakpoint {
+	saved := []SavedBreakpoint{}
+	for _, bp := range nav.GetAllBreakpoints() {
+		if bp.ID >= 0 && !bp.Temporary {
+			saved = append(saved, bp.ToSaved())
+		}
+	}
+	sort.Slice(saved, func(i, j int) bool {
+		a, b := saved[i], saved[j]
+		return a.File < b.File || a.File == b.File && a.Line < b.Line
+	})
+	return saved
+}
+
+// Load saved session. A missing file results in an empty session.
+func LoadSession(path string) (*Session, error) {
+	session := Session{CurrentLines: make(map[string]int)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &session, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Save breakpoints and file positions along with the given page index.
+func (nav *Nav) SaveSession(path string, page int) error {
+	session := Session{
+		Breakpoints:  nav.SavedBreakpoints(),
+		CurrentLines: nav.CurrentLines,
+		Page:         page,
+	}
+
+	data, err := json.MarshalIndent(&session, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
 }
 
 func NewNav(projectPath string) Nav {
@@ -99,6 +234,8 @@ func NewNav(projectPath string) Nav {
 		FileCache:   make(map[string]*File),
 		CurrentLines: make(map[string]int),
 		Breakpoints: make(map[string] map[int]*UiBreakpoint),
+		Watchpoints: make(map[int]*Watchpoint),
+		Catchpoints: make(map[string]*api.Breakpoint),
 		Goroutines: []*api.Goroutine{},
 	}
 }
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Page listing lines of text as they come, such as the output of the program.
+type OutputPage struct {
+	name           string
+	title          string
+	commandHandler *CommandHandler
+	textView       *tview.TextView
+	widget         *tview.Frame
+
+	lines []string // Formatted lines, at most gConfig.OutputLines.
+	plain []string // Lines without color tags, used for searching.
+
+	search  string
+	matches []int
+	matchI  int
+}
+
+func NewOutputPage(app *tview.Application, name string, title string) *OutputPage {
+	textView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false).
+		SetChangedFunc(func() {
+			app.Draw()
+		})
+	textView.SetBackgroundColor(tcell.ColorDefault)
+
+	pageFrame := tview.NewFrame(textView).
+		SetBorders(0, 0, 0, 0, 0, 0)
+	pageFrame.SetBackgroundColor(tcell.ColorDefault)
+
+	op := OutputPage{
+		name:     name,
+		title:    title,
+		textView: textView,
+		widget:   pageFrame,
+	}
+	op.renderHeader()
+	return &op
+}
+
+func (page *OutputPage) renderHeader() {
+	page.widget.Clear()
+	header := fmt.Sprintf("[%s::b]%s:", iToColorS(gConfig.Colors.HeaderFg), page.title)
+	if page.search != "" {
+		header += fmt.Sprintf(" [%s::-]/%s (%d/%d)",
+			iToColorS(gConfig.Colors.VarValueFg),
+			tview.Escape(page.search),
+			page.matchI+1,
+			len(page.matches),
+		)
+	}
+	page.widget.AddText(header, true, tview.AlignLeft, tcell.ColorWhite)
+}
+
+func (page *OutputPage) addLine(formatted string, plain string) {
+	page.lines = append(page.lines, formatted)
+	page.plain = append(page.plain, plain)
+
+	// Drop the oldest lines once the buffer is full. Drop some extra so that
+	// the whole buffer doesn't have to be rendered again on every new line.
+	if len(page.lines) > gConfig.OutputLines {
+		drop := len(page.lines) - gConfig.OutputLines + gConfig.OutputLines/10
+		if drop > len(page.lines) {
+			drop = len(page.lines)
+		}
+		page.lines = page.lines[drop:]
+		page.plain = page.plain[drop:]
+		page.findMatches()
+		page.render()
+		return
+	}
+
+	follow := page.isAtEnd()
+	fmt.Fprint(page.textView, formatted+"\n")
+	if follow {
+		page.textView.ScrollToEnd()
+	}
+}
+
+// Whether the view is scrolled to the last line and should follow new output.
+func (page *OutputPage) isAtEnd() bool {
+	row, _ := page.textView.GetScrollOffset()
+	_, _, _, h := page.textView.GetInnerRect()
+	return row+h >= len(page.lines)-1
+}
+
+func (page *OutputPage) AddOutput(line *OutputLine) {
+	color := gConfig.Colors.OutputFg
+	if line.Stderr {
+		color = gConfig.Colors.OutputStderrFg
+	}
+	page.addLine(fmt.Sprintf("[%s]%s", iToColorS(color), tview.Escape(line.Text)), line.Text)
+}
+
+// Add a line of text following a highlighted prefix, such as its origin.
+func (page *OutputPage) AddEntry(prefix string, text string) {
+	page.addLine(fmt.Sprintf("[%s]%s [%s]%s",
+		iToColorS(gConfig.Colors.LineFg),
+		tview.Escape(prefix),
+		iToColorS(gConfig.Colors.OutputFg),
+		tview.Escape(text),
+	), prefix+" "+text)
+}
+
+// Add a line marking an event such as the debugger stopping.
+func (page *OutputPage) AddMarker(msg string) {
+	marker := fmt.Sprintf("── %s ──", msg)
+	page.addLine(fmt.Sprintf("[%s::b]%s", iToColorS(gConfig.Colors.OutputMarkerFg), tview.Escape(marker)), marker)
+}
+
+func (page *OutputPage) render() {
+	matchSet := make(map[int]bool)
+	for _, m := range page.matches {
+		matchSet[m] = true
+	}
+	var sb strings.Builder
+	for i, l := range page.lines {
+		if matchSet[i] {
+			sb.WriteString(fmt.Sprintf(`["%d"]%s[""]`, i, l))
+		} else {
+			sb.WriteString(l)
+		}
+		sb.WriteString("\n")
+	}
+	page.textView.SetText(sb.String())
+	page.textView.ScrollToEnd()
+}
+
+func (page *OutputPage) findMatches() {
+	page.matches = []int{}
+	if page.search != "" {
+		for i, l := range page.plain {
+			if strings.Contains(l, page.search) {
+				page.matches = append(page.matches, i)
+			}
+		}
+	}
+	page.matchI = len(page.matches) - 1
+}
+
+// Highlight lines containing text and jump to the last one.
+func (page *OutputPage) Search(text string) {
+	page.search = text
+	page.findMatches()
+	page.render()
+	page.jumpToMatch()
+}
+
+func (page *OutputPage) jumpToMatch() {
+	page.renderHeader()
+	if len(page.matches) == 0 {
+		page.textView.Highlight()
+		return
+	}
+	line := page.matches[page.matchI]
+	page.textView.Highlight(fmt.Sprint(line))
+	_, _, _, h := page.textView.GetInnerRect()
+	row := line - h/2
+	if row < 0 {
+		row = 0
+	}
+	page.textView.ScrollTo(row, 0)
+}
+
+func (page *OutputPage) GetWidget() tview.Primitive {
+	return page.widget
+}
+
+func (page *OutputPage) GetName() string {
+	return page.name
+}
+
+func (page *OutputPage) SetCommandHandler(ch *CommandHandler) {
+	page.commandHandler = ch
+}
+
+func (page *OutputPage) HandleKeyEvent(event *tcell.EventKey) *tcell.EventKey {
+	if keyPressed(event, gConfig.Keys.NextMatch) && len(page.matches) > 0 {
+		page.matchI = (page.matchI + 1) % len(page.matches)
+		page.jumpToMatch()
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.PrevMatch) && len(page.matches) > 0 {
+		page.matchI = (page.matchI - 1 + len(page.matches)) % len(page.matches)
+		page.jumpToMatch()
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.LineDown) {
+		row, _ := page.textView.GetScrollOffset()
+		page.textView.ScrollTo(row+1, 0)
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.LineUp) {
+		row, _ := page.textView.GetScrollOffset()
+		if row > 0 {
+			page.textView.ScrollTo(row-1, 0)
+		}
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.PageTop) {
+		page.textView.ScrollToBeginning()
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.PageEnd) {
+		page.textView.ScrollToEnd()
+		return nil
+	}
+	page.textView.InputHandler()(event, func(p tview.Primitive) {})
+	return nil
+}
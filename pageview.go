@@ -23,6 +23,10 @@ const (
 	IVarsPage                  = 2
 	IStackPage                 = 3
 	IGoroutinePage             = 4
+	ITestsPage                 = 5
+	IOutputPage                = 6
+	ITraceLogPage              = 7
+	IPanicPage                 = 8
 )
 
 type PageView struct {
@@ -36,6 +40,10 @@ type PageView struct {
 	varsPage        *VarsPage
 	stackPage       *StackPage
 	goroutinePage   *GoroutinePage
+	testsPage       *TestsPage
+	outputPage      *OutputPage
+	traceLogPage    *OutputPage
+	panicPage       *PanicPage
 }
 
 func NewPageView(cmdHdlr *CommandHandler, nav *nav.Nav, app *tview.Application) *PageView {
@@ -49,8 +57,12 @@ func NewPageView(cmdHdlr *CommandHandler, nav *nav.Nav, app *tview.Application)
 		varsPage:        NewVarPage(),
 		stackPage:       NewStackPage(),
 		goroutinePage:   NewGoroutinePage(),
+		testsPage:       NewTestsPage(),
+		outputPage:      NewOutputPage(app, "output", "Output"),
+		traceLogPage:    NewOutputPage(app, "tracelog", "Trace log"),
+		panicPage:       NewPanicPage(),
 	}
-	pv.pages = []Page{pv.codePage, pv.breakpointsPage, pv.varsPage, pv.stackPage, pv.goroutinePage}
+	pv.pages = []Page{pv.codePage, pv.breakpointsPage, pv.varsPage, pv.stackPage, pv.goroutinePage, pv.testsPage, pv.outputPage, pv.traceLogPage, pv.panicPage}
 
 	for _, p := range pv.pages {
 		pv.pagesView.AddPage(p.GetName(), p.GetWidget(), true, true)
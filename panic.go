@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-delve/delve/service/api"
+	"github.com/go-delve/delve/service/rpc2"
+	"github.com/ilmari-h/dlvtui/nav"
+	log "github.com/sirupsen/logrus"
+)
+
+// Breakpoint that stops the program on an event of the runtime rather than on
+// a line of code.
+type catchpoint struct {
+	Name        string // Name of the breakpoint in the backend.
+	Function    string // Function the breakpoint is created in, empty if the backend creates it.
+	Option      *bool  // Configuration option that enables the breakpoint.
+	Description string // Shown in the breakpoints page.
+	Title       string // Heading of the panic page when the program stops on it.
+	Value       string // Expression evaluated in the stopped goroutine.
+}
+
+var catchpoints = []*catchpoint{
+	{
+		Name:        "unrecovered-panic",
+		Description: "unrecovered panics",
+		Title:       "panic",
+		Value:       "runtime.curg._panic.arg",
+	},
+	{
+		Name:        "runtime-fatal-throw",
+		Description: "fatal errors",
+		Title:       "fatal error",
+		Value:       "s",
+	},
+	{
+		Name:        "panic",
+		Function:    "runtime.gopanic",
+		Option:      &gConfig.BreakOnRecoveredPanics,
+		Description: "all panics, including recovered ones",
+		Title:       "panic",
+		Value:       "e",
+	},
+	{
+		Name:        "exit",
+		Function:    "os.Exit",
+		Option:      &gConfig.BreakOnExit,
+		Description: "os.Exit and log.Fatal",
+		Title:       "exit status",
+		Value:       "code",
+	},
+}
+
+func breakpointName(bp *api.Breakpoint) string {
+	if bp == nil {
+		return ""
+	}
+	return bp.Name
+}
+
+func findCatchpoint(name string) *catchpoint {
+	if name == "" {
+		return nil
+	}
+	for _, cp := range catchpoints {
+		if cp.Name == name {
+			return cp
+		}
+	}
+	return nil
+}
+
+// Program stopped on a catchpoint.
+type PanicStop struct {
+	Catchpoint  *catchpoint
+	Value       string
+	GoroutineID int
+	Stack       []api.Stackframe // Whole stack of the goroutine.
+	Cause       int              // Index of the frame that caused the stop, -1 if not found.
+}
+
+func createCatchpoint(client *rpc2.RPCClient, cp *catchpoint) error {
+	_, err := client.CreateBreakpoint(&api.Breakpoint{
+		Name:         cp.Name,
+		FunctionName: cp.Function,
+		Goroutine:    true,
+		LoadArgs:     &gConfig.LoadConfig,
+		LoadLocals:   &gConfig.LoadConfig,
+	})
+	return err
+}
+
+// Create the catchpoints enabled in the configuration that don't exist yet.
+func createCatchpoints(view *View, client *rpc2.RPCClient) {
+	for _, cp := range catchpoints {
+		if cp.Option == nil || !*cp.Option {
+			continue
+		}
+		if _, err := client.GetBreakpointByName(cp.Name); err == nil {
+			continue
+		}
+		if err := createCatchpoint(client, cp); err != nil {
+			log.Printf("rpc error: %s", err.Error())
+			view.showNotification(fmt.Sprintf("Could not stop on %s: %s", cp.Description, err.Error()), true)
+		}
+	}
+}
+
+// Load the stack of the goroutine stopped on a catchpoint and the value that
+// was panicked with or passed to os.Exit.
+func loadPanicStop(view *View, client *rpc2.RPCClient, cp *catchpoint, th *api.Thread) (*PanicStop, error) {
+	stack, err := client.Stacktrace(th.GoroutineID, 100, api.StacktraceSimple, &gConfig.LoadConfig)
+	if err != nil {
+		return nil, err
+	}
+	localizeStack(stack)
+
+	value := "?"
+	v, err := client.EvalVariable(api.EvalScope{GoroutineID: th.GoroutineID}, cp.Value, gConfig.LoadConfig)
+	if err != nil {
+		log.Printf("rpc error: %s", err.Error())
+	} else {
+		value = v.SinglelineString()
+	}
+	return &PanicStop{
+		Catchpoint:  cp,
+		Value:       value,
+		GoroutineID: th.GoroutineID,
+		Stack:       stack,
+		Cause:       causeFrame(view.navState, stack),
+	}, nil
+}
+
+// Functions that are called on the way to a panic or an exit.
+func internalFunction(name string) bool {
+	return strings.HasPrefix(name, "runtime.") || strings.HasPrefix(name, "log.") || name == "os.Exit"
+}
+
+// The topmost frame inside the project, or outside of the runtime if there are
+// none or the project isn't known.
+func causeFrame(navState *nav.Nav, stack []api.Stackframe) int {
+	for i, sf := range stack {
+		if navState.ProjectPath != "" && navState.InProject(sf.File) {
+			return i
+		}
+	}
+	for i, sf := range stack {
+		if sf.Function != nil && !internalFunction(sf.Function.Name()) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/go-delve/delve/service/api"
+	"github.com/rivo/tview"
+)
+
+// Shows the last panic, fatal error or exit the program stopped on, along
+// with the whole stack of the goroutine.
+type PanicPage struct {
+	commandHandler *CommandHandler
+	summary        *tview.TextView
+	listView       *tview.List
+	widget         *tview.Frame
+}
+
+func NewPanicPage() *PanicPage {
+	summary := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true).
+		SetText(fmt.Sprintf("[%s]The program hasn't panicked.", iToColorS(gConfig.Colors.VarAddrFg)))
+	summary.SetBackgroundColor(tcell.ColorDefault)
+
+	listView := tview.NewList()
+	listView.SetBackgroundColor(tcell.ColorDefault)
+
+	selectedStyle := tcell.StyleDefault.
+		Foreground(iToColorTcell(gConfig.Colors.LineFg)).
+		Background(iToColorTcell(gConfig.Colors.ListSelectedBg)).
+		Attributes(tcell.AttrBold)
+
+	listView.SetSelectedStyle(selectedStyle)
+	listView.SetInputCapture(listInputCaptureC)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(summary, 4, 0, false).
+		AddItem(listView, 0, 1, true)
+
+	pageFrame := tview.NewFrame(flex).
+		SetBorders(0, 0, 0, 0, 0, 0).
+		AddText("[::b]Panic:", true, tview.AlignLeft, iToColorTcell(gConfig.Colors.HeaderFg))
+	pageFrame.SetBackgroundColor(tcell.ColorDefault)
+
+	return &PanicPage{
+		summary:  summary,
+		listView: listView,
+		widget:   pageFrame,
+	}
+}
+
+func (page *PanicPage) RenderPanic(stop *PanicStop) {
+	cause := "no frame outside of the runtime"
+	if stop.Cause >= 0 {
+		sf := stop.Stack[stop.Cause]
+		cause = fmt.Sprintf("%s %s:%d", frameFunctionName(&sf), sf.File, sf.Line)
+	}
+	page.summary.SetText(fmt.Sprintf("[%s::b]%s:[-::-] %s\n[%s]in goroutine %d, caused by[-] %s",
+		iToColorS(gConfig.Colors.NotifErrorFg),
+		stop.Catchpoint.Title,
+		tview.Escape(stop.Value),
+		iToColorS(gConfig.Colors.VarAddrFg),
+		stop.GoroutineID,
+		tview.Escape(cause),
+	))
+
+	page.listView.Clear()
+	stack := stop.Stack
+	for i, frame := range stack {
+		name := frameFunctionName(&frame)
+		color := gConfig.Colors.VarAddrFg
+		if i == stop.Cause {
+			color = gConfig.Colors.VarTypeFg
+		} else if page.commandHandler.view.navState.InProject(frame.File) {
+			color = gConfig.Colors.VarValueFg
+		}
+		page.listView.AddItem(
+			fmt.Sprintf("[%s]%s", iToColorS(color), tview.Escape(name)),
+			fmt.Sprintf("[%s]%s[white]:%d",
+				iToColorS(gConfig.Colors.VarNameFg),
+				frame.File,
+				frame.Line,
+			),
+			0,
+			nil).
+			SetSelectedFunc(func(i int, s1, s2 string, r rune) {
+				page.commandHandler.RunCommand(&OpenFile{
+					File:   stack[i].File,
+					AtLine: stack[i].Line - 1,
+				})
+			})
+	}
+	if stop.Cause >= 0 {
+		page.listView.SetCurrentItem(stop.Cause)
+	}
+}
+
+func frameFunctionName(sf *api.Stackframe) string {
+	if sf.Function == nil {
+		return "?"
+	}
+	return sf.Function.Name()
+}
+
+func (page *PanicPage) GetWidget() tview.Primitive {
+	return page.widget
+}
+
+func (page *PanicPage) GetName() string {
+	return "panic"
+}
+
+func (page *PanicPage) SetCommandHandler(ch *CommandHandler) {
+	page.commandHandler = ch
+}
+
+func (page *PanicPage) HandleKeyEvent(event *tcell.EventKey) *tcell.EventKey {
+	if keyPressed(event, gConfig.Keys.LineDown) {
+		page.listView.SetCurrentItem(page.listView.GetCurrentItem() + 1)
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.LineUp) {
+		if page.listView.GetCurrentItem() > 0 {
+			page.listView.SetCurrentItem(page.listView.GetCurrentItem() - 1)
+		}
+		return nil
+	}
+	page.listView.InputHandler()(event, func(p tview.Primitive) {})
+	return nil
+}
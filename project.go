@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/go-delve/delve/service/api"
+	"github.com/go-delve/delve/service/rpc2"
+	log "github.com/sirupsen/logrus"
+)
+
+// Find the root of the module or workspace containing dir by looking for
+// go.work and go.mod files in it and its parents. A workspace takes precedence
+// over the modules in it.
+func findModuleRoot(dir string) string {
+	root := ""
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.work")); err == nil {
+			return dir
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && root == "" {
+			root = dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return root
+		}
+		dir = parent
+	}
+}
+
+// Directory of the file containing the main function of the target, as reported by the backend.
+func mainPackageDir(client *rpc2.RPCClient) string {
+	locs, err := client.FindLocation(api.EvalScope{GoroutineID: -1}, "main.main", false, nil)
+	if err != nil || len(locs) == 0 {
+		log.Printf("Could not find location of main.main: %v", err)
+		return ""
+	}
+	return filepath.Dir(toLocalPath(locs[0].File))
+}
+
+// Resolve the project root directory. In order of preference it's the directory
+// given with -root, the module containing the debugged package, the module containing
+// the main package, the module containing the working directory, or the directory
+// of the main package.
+func resolveProjectDir(client *rpc2.RPCClient) string {
+	if projectRoot != "" {
+		abs, _ := filepath.Abs(projectRoot)
+		return abs
+	}
+
+	candidates := []string{}
+	if (debugMode || testMode) && target != "" {
+		if abs, err := filepath.Abs(target); err == nil {
+			candidates = append(candidates, abs)
+		}
+	}
+	mainDir := mainPackageDir(client)
+	if mainDir != "" {
+		candidates = append(candidates, mainDir)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		candidates = append(candidates, wd)
+	}
+
+	for _, dir := range candidates {
+		if root := findModuleRoot(dir); root != "" {
+			return root
+		}
+	}
+	return mainDir
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindModuleRoot(t *testing.T) {
+	root := t.TempDir()
+	dirs := []string{
+		"mod/cmd/server",
+		"work/a/pkg",
+		"work/b",
+		"none/pkg",
+	}
+	for _, d := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := []string{
+		"mod/go.mod",
+		"work/go.work",
+		"work/a/go.mod",
+		"work/b/go.mod",
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(root, f), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"mod", "mod"},
+		{"mod/cmd/server", "mod"},
+		{"work/a/pkg", "work"},
+		{"work/b", "work"},
+		{"none/pkg", ""},
+	}
+	for _, tt := range tests {
+		want := ""
+		if tt.want != "" {
+			want = filepath.Join(root, tt.want)
+		}
+		if got := findModuleRoot(filepath.Join(root, tt.dir)); got != want {
+			t.Errorf("findModuleRoot(%q) = %q, want %q", tt.dir, got, want)
+		}
+	}
+}
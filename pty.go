@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func ioctl(fd uintptr, req uintptr, arg uintptr) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, arg); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// Open a pseudo-terminal, returning its master end and the path of its slave end.
+func openPty() (*os.File, string, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	var n uint32
+	if err := ioctl(master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); err != nil {
+		master.Close()
+		return nil, "", err
+	}
+	var unlock int32
+	if err := ioctl(master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		master.Close()
+		return nil, "", err
+	}
+	return master, fmt.Sprintf("/dev/pts/%d", n), nil
+}
+
+// Attach the target to a pseudo-terminal by setting the tty of opts.
+// Output of the terminal is sent to outputChan, input can be written to the returned file.
+// The returned function closes the terminal.
+func attachPty(opts *ProgramOptions, outputChan chan *OutputLine) (*os.File, func(), error) {
+	master, slavePath, err := openPty()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Keep the slave end open so that reads don't fail while the target is restarting.
+	slave, err := os.OpenFile(slavePath, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	cleanup := func() {
+		slave.Close()
+		master.Close()
+	}
+
+	go func() {
+		in := bufio.NewScanner(master)
+		in.Buffer(make([]byte, 64*1024), 1024*1024)
+		for in.Scan() {
+			outputChan <- &OutputLine{Text: strings.TrimRight(in.Text(), "\r")}
+		}
+		if err := in.Err(); err != nil {
+			log.Printf("Error reading pseudo-terminal: %s", err)
+		}
+	}()
+
+	opts.Tty = slavePath
+	return master, cleanup, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type OutputLine struct {
+	Text   string
+	Stderr bool
+}
+
+// Create named pipes in dir for the stdout and stderr of the target.
+func createOutputPipes(dir string) (string, string, error) {
+	stdout := filepath.Join(dir, "stdout")
+	stderr := filepath.Join(dir, "stderr")
+	for _, p := range []string{stdout, stderr} {
+		if err := syscall.Mkfifo(p, 0600); err != nil {
+			return "", "", err
+		}
+	}
+	return stdout, stderr, nil
+}
+
+// Forward lines written to a named pipe to outputChan.
+// The pipe is reopened whenever the target closes it, e.g. on restart.
+func readOutputPipe(path string, stderr bool, outputChan chan *OutputLine) {
+	for {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Printf("Error opening output pipe %s: %s", path, err)
+			return
+		}
+		in := bufio.NewScanner(f)
+		in.Buffer(make([]byte, 64*1024), 1024*1024)
+		for in.Scan() {
+			outputChan <- &OutputLine{Text: in.Text(), Stderr: stderr}
+		}
+		if err := in.Err(); err != nil {
+			log.Printf("Error reading output pipe %s: %s", path, err)
+		}
+		f.Close()
+	}
+}
+
+// Redirect stdout and stderr of the target to outputChan by setting the redirects of opts.
+// Returns a function for removing the pipes.
+func redirectOutput(opts *ProgramOptions, outputChan chan *OutputLine) func() {
+	dir, err := os.MkdirTemp("", "dlvtui-output")
+	if err != nil {
+		log.Printf("Error creating output directory: %s", err)
+		return func() {}
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	stdout, stderr, err := createOutputPipes(dir)
+	if err != nil {
+		log.Printf("Error creating output pipes: %s", err)
+		return cleanup
+	}
+	go readOutputPipe(stdout, false, outputChan)
+	go readOutputPipe(stderr, true, outputChan)
+	opts.Redirects[1] = stdout
+	opts.Redirects[2] = stderr
+	return cleanup
+}
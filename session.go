@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-delve/delve/service/api"
+	"github.com/go-delve/delve/service/rpc2"
+	"github.com/ilmari-h/dlvtui/nav"
+	"github.com/rivo/tview"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+func dataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "share")
+}
+
+// Sessions are stored per project, named after the escaped project path.
+func sessionPath(projectPath string) string {
+	return filepath.Join(dataDir(), "dlvtui", "sessions", url.PathEscape(projectPath)+".json")
+}
+
+// Recreate the breakpoints and restore the positions of the previous session
+// of the project.
+func restoreSession(view *View, app *tview.Application, client *rpc2.RPCClient) {
+	path := sessionPath(view.navState.ProjectPath)
+	session, err := nav.LoadSession(path)
+	if err != nil {
+		log.Printf("Error loading session %s: %s", path, err)
+		return
+	}
+	log.Printf("Restoring %d breakpoints from %s", len(session.Breakpoints), path)
+
+	// A server that was connected to may have the breakpoints already.
+	existing := make(map[string]map[int]bool)
+	if view.remote {
+		bps, _ := client.ListBreakpoints(false)
+		for _, bp := range bps {
+			localizeBreakpoint(bp)
+			if existing[bp.File] == nil {
+				existing[bp.File] = make(map[int]bool)
+			}
+			existing[bp.File][bp.Line] = true
+		}
+	}
+
+	failed := []string{}
+	for _, saved := range session.Breakpoints {
+		if existing[saved.File][saved.Line] {
+			continue
+		}
+		if err := restoreBreakpoint(view, client, saved); err != nil {
+			failed = append(failed, fmt.Sprintf("%s:%d (%s)", saved.File, saved.Line, err))
+		}
+	}
+	if len(failed) > 0 {
+		view.showNotification(fmt.Sprintf("Could not restore %d breakpoints: %s", len(failed), strings.Join(failed, ", ")), true)
+	}
+
+	for file, line := range session.CurrentLines {
+		if _, ok := view.navState.CurrentLines[file]; !ok {
+			view.navState.CurrentLines[file] = line
+		}
+	}
+	if session.Page > 0 && session.Page < len(view.pageView.pages) {
+		view.pageView.SwitchToPage(PageIndex(session.Page))
+	}
+}
+
+// Create a saved breakpoint. A disabled one is only added to the UI once its
+// location has been found.
+func restoreBreakpoint(view *View, client *rpc2.RPCClient, saved nav.SavedBreakpoint) error {
+	if !saved.Disabled {
+		create := CreateBreakpoint{
+			Line:       saved.Line,
+			File:       saved.File,
+			Cond:       saved.Cond,
+			HitCond:    saved.HitCond,
+			LogMessage: saved.LogMessage,
+			Group:      saved.Group,
+			Actions:    saved.Actions,
+			Label:      saved.Label,
+		}
+		bp, err := create.create(client)
+		if err != nil {
+			return err
+		}
+		view.breakpointChan <- bp
+		return nil
+	}
+
+	loc := fmt.Sprintf("%s:%d", toBackendPath(saved.File), saved.Line)
+	if _, err := client.FindLocation(api.EvalScope{GoroutineID: -1}, loc, false, nil); err != nil {
+		return err
+	}
+	view.breakpointChan <- &nav.UiBreakpoint{
+		Disabled:   true,
+		LogMessage: saved.LogMessage,
+		Group:      saved.Group,
+		Actions:    saved.Actions,
+		Label:      saved.Label,
+		Breakpoint: &api.Breakpoint{
+			File:         saved.File,
+			Line:         saved.Line,
+			FunctionName: saved.FunctionName,
+			Cond:         saved.Cond,
+			HitCond:      saved.HitCond,
+			Tracepoint:   saved.LogMessage != "",
+		},
+	}
+	return nil
+}
+
+// Breakpoints written by :bexport. Paths inside the project are relative to
+// its root, so the file can be used on other machines.
+type breakpointsFile struct {
+	Breakpoints []nav.SavedBreakpoint `json:"breakpoints" yaml:"breakpoints"`
+}
+
+func isYamlPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func writeBreakpointsFile(path string, projectPath string, bps []nav.SavedBreakpoint) error {
+	for i := range bps {
+		if rel, err := filepath.Rel(projectPath, bps[i].File); err == nil && !strings.HasPrefix(rel, "..") {
+			bps[i].File = rel
+		}
+	}
+	var data []byte
+	var err error
+	if isYamlPath(path) {
+		data, err = yaml.Marshal(&breakpointsFile{bps})
+	} else {
+		data, err = json.MarshalIndent(&breakpointsFile{bps}, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func readBreakpointsFile(path string, projectPath string) ([]nav.SavedBreakpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file breakpointsFile
+	if isYamlPath(path) {
+		err = yaml.Unmarshal(data, &file)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range file.Breakpoints {
+		if !filepath.IsAbs(file.Breakpoints[i].File) {
+			file.Breakpoints[i].File = filepath.Join(projectPath, file.Breakpoints[i].File)
+		}
+	}
+	return file.Breakpoints, nil
+}
+
+func (view *View) saveSession() {
+	if view.navState.ProjectPath == "" || view.readOnly {
+		return
+	}
+	path := sessionPath(view.navState.ProjectPath)
+	if err := view.navState.SaveSession(path, view.pageView.index); err != nil {
+		log.Printf("Error saving session %s: %s", path, err)
+	}
+}
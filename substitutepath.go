@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-delve/delve/service/api"
+)
+
+// Rule replacing the prefix From of paths reported by the backend with To.
+type PathRule struct {
+	From string
+	To   string
+}
+
+// Parse a rule given in the form from:to.
+func parsePathRule(s string) (PathRule, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return PathRule{}, fmt.Errorf("invalid path substitution rule %s, expected from:to", s)
+	}
+	return PathRule{From: parts[0], To: parts[1]}, nil
+}
+
+func replacePathPrefix(path string, from string, to string) (string, bool) {
+	from = strings.TrimSuffix(from, "/")
+	to = strings.TrimSuffix(to, "/")
+	if path == from {
+		return to, true
+	}
+	if strings.HasPrefix(path, from+"/") {
+		return to + path[len(from):], true
+	}
+	return path, false
+}
+
+// Map a path reported by the backend to a local path.
+func toLocalPath(path string) string {
+	for _, rule := range gConfig.SubstitutePath {
+		if res, ok := replacePathPrefix(path, rule.From, rule.To); ok {
+			return res
+		}
+	}
+	return path
+}
+
+// Map a local path to the path known by the backend.
+func toBackendPath(path string) string {
+	for _, rule := range gConfig.SubstitutePath {
+		if res, ok := replacePathPrefix(path, rule.To, rule.From); ok {
+			return res
+		}
+	}
+	return path
+}
+
+// Rules in the format taken by FindLocation.
+func backendPathRules() [][2]string {
+	rules := [][2]string{}
+	for _, rule := range gConfig.SubstitutePath {
+		rules = append(rules, [2]string{rule.From, rule.To})
+	}
+	return rules
+}
+
+func localizeBreakpoint(bp *api.Breakpoint) {
+	if bp != nil {
+		bp.File = toLocalPath(bp.File)
+	}
+}
+
+func localizeLocation(loc *api.Location) {
+	loc.File = toLocalPath(loc.File)
+}
+
+func localizeStack(frames []api.Stackframe) {
+	for i := range frames {
+		localizeLocation(&frames[i].Location)
+	}
+}
+
+func localizeGoroutine(g *api.Goroutine) {
+	if g == nil {
+		return
+	}
+	localizeLocation(&g.CurrentLoc)
+	localizeLocation(&g.UserCurrentLoc)
+	localizeLocation(&g.GoStatementLoc)
+	localizeLocation(&g.StartLoc)
+}
+
+func localizeState(state *api.DebuggerState) {
+	if state == nil {
+		return
+	}
+	current := false
+	for _, th := range state.Threads {
+		th.File = toLocalPath(th.File)
+		localizeBreakpoint(th.Breakpoint)
+		current = current || th == state.CurrentThread
+	}
+	if state.CurrentThread != nil && !current {
+		state.CurrentThread.File = toLocalPath(state.CurrentThread.File)
+		localizeBreakpoint(state.CurrentThread.Breakpoint)
+	}
+	localizeGoroutine(state.SelectedGoroutine)
+}
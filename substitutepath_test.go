@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestReplacePathPrefix(t *testing.T) {
+	tests := []struct {
+		path, from, to string
+		want           string
+		ok             bool
+	}{
+		{"/build/src/main.go", "/build", "/home/user", "/home/user/src/main.go", true},
+		{"/build/src/main.go", "/build/", "/home/user/", "/home/user/src/main.go", true},
+		{"/build", "/build", "/home/user", "/home/user", true},
+		{"/builder/main.go", "/build", "/home/user", "/builder/main.go", false},
+		{"/other/main.go", "/build", "/home/user", "/other/main.go", false},
+	}
+	for _, tt := range tests {
+		got, ok := replacePathPrefix(tt.path, tt.from, tt.to)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("replacePathPrefix(%q, %q, %q) = %q, %v, want %q, %v",
+				tt.path, tt.from, tt.to, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParsePathRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    PathRule
+		wantErr bool
+	}{
+		{"/build:/home/user", PathRule{From: "/build", To: "/home/user"}, false},
+		{"/build:", PathRule{From: "/build", To: ""}, false},
+		{":/home/user", PathRule{}, true},
+		{"/build", PathRule{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parsePathRule(tt.rule)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parsePathRule(%q) = %v, %v, want %v, error %v", tt.rule, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
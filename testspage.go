@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+type TestsPage struct {
+	renderedTests  []string
+	filter         string
+	commandHandler *CommandHandler
+	listView       *tview.List
+	widget         *tview.Frame
+}
+
+func NewTestsPage() *TestsPage {
+	listView := tview.NewList()
+	listView.SetBackgroundColor(tcell.ColorDefault)
+	listView.ShowSecondaryText(false)
+
+	selectedStyle := tcell.StyleDefault.
+		Foreground(iToColorTcell(gConfig.Colors.LineFg)).
+		Background(iToColorTcell(gConfig.Colors.ListSelectedBg)).
+		Attributes(tcell.AttrBold)
+
+	listView.SetSelectedStyle(selectedStyle)
+
+	listView.SetInputCapture(listInputCaptureC)
+
+	pageFrame := tview.NewFrame(listView).
+		SetBorders(0, 0, 0, 0, 0, 0).
+		AddText("[::b]Tests:", true, tview.AlignLeft, iToColorTcell(gConfig.Colors.HeaderFg))
+	pageFrame.SetBackgroundColor(tcell.ColorDefault)
+	tp := TestsPage{
+		listView: listView,
+		widget:   pageFrame,
+	}
+	return &tp
+}
+
+func (page *TestsPage) SetCommandHandler(ch *CommandHandler) {
+	page.commandHandler = ch
+}
+
+// Set the -test.run filter the program was last started with.
+func (page *TestsPage) SetFilter(filter string) {
+	page.filter = filter
+	page.RenderTests(page.renderedTests)
+}
+
+func (page *TestsPage) RenderTests(tests []string) {
+	page.renderedTests = tests
+
+	var filterRe *regexp.Regexp
+	if page.filter != "" {
+		filterRe, _ = regexp.Compile(page.filter)
+	}
+
+	selectedI := page.listView.GetCurrentItem()
+	page.listView.Clear()
+	for _, test := range tests {
+		label := fmt.Sprintf("  [%s]%s", iToColorS(gConfig.Colors.VarNameFg), test)
+		if filterRe != nil && filterRe.MatchString(test) {
+			label = fmt.Sprintf("> [%s::b]%s", iToColorS(gConfig.Colors.VarTypeFg), test)
+		}
+		page.listView.AddItem(
+			label,
+			"",
+			0,
+			nil).
+			SetSelectedFunc(func(i int, s1, s2 string, r rune) {
+				test := page.renderedTests[i]
+				page.commandHandler.RunCommand(&RunTests{
+					Filter: "^" + regexp.QuoteMeta(test) + "$",
+					Bench:  strings.HasPrefix(test, "Benchmark"),
+				})
+			})
+	}
+	page.listView.SetCurrentItem(selectedI)
+}
+
+func (page *TestsPage) GetWidget() tview.Primitive {
+	return page.widget
+}
+
+func (page *TestsPage) GetName() string {
+	return "tests"
+}
+
+func (page *TestsPage) HandleKeyEvent(event *tcell.EventKey) *tcell.EventKey {
+	if keyPressed(event, gConfig.Keys.LineDown) {
+		page.listView.SetCurrentItem(page.listView.GetCurrentItem() + 1)
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.LineUp) {
+		if page.listView.GetCurrentItem() > 0 {
+			page.listView.SetCurrentItem(page.listView.GetCurrentItem() - 1)
+		}
+		return nil
+	}
+	page.listView.InputHandler()(event, func(p tview.Primitive) {})
+	return nil
+}
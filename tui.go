@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"strings"
 	"time"
@@ -10,7 +11,6 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/go-delve/delve/service/api"
-	"github.com/go-delve/delve/service/rpc2"
 	"github.com/rivo/tview"
 	log "github.com/sirupsen/logrus"
 )
@@ -33,13 +33,46 @@ type DebuggerStep struct {
 	globals []api.Variable
 }
 
+// Line written to the output page by a command.
+type OutputEntry struct {
+	Prefix string
+	Text   string
+}
+
+// Variable of the locals page loaded again with larger limits.
+type LoadedVariable struct {
+	Node *tview.TreeNode
+	Var  *api.Variable
+}
+
+// A logpoint was passed by a goroutine.
+type TraceHit struct {
+	Breakpoint  *api.Breakpoint
+	Info        *api.BreakpointInfo
+	GoroutineID int
+}
+
 type DebuggerMove struct {
-	DbgState *api.DebuggerState
-	Stack    []api.Stackframe
+	DbgState    *api.DebuggerState
+	Stack       []api.Stackframe
+	Breakpoints []*api.Breakpoint // State after the program ran, nil if it didn't.
+	Panic       *PanicStop        // Set if stopped on a panic, a fatal error or an exit.
+}
+
+// Location to show for the move. For panics it's the frame that caused it
+// rather than the runtime function the program stopped in.
+func (move *DebuggerMove) Location() (string, int) {
+	if move.Panic != nil && move.Panic.Cause >= 0 {
+		sf := move.Panic.Stack[move.Panic.Cause]
+		return sf.File, sf.Line
+	}
+	return move.DbgState.CurrentThread.File, move.DbgState.CurrentThread.Line
 }
 
 type View struct {
 	nwBlocking bool
+	readOnly   bool // Target can be inspected but not executed, e.g. a core dump.
+	remote     bool // Connected to a backend that was not started by this client.
 
 	commandChan chan string
 	keyHandler  KeyHandler
@@ -55,12 +88,26 @@ type View struct {
 	cmdHandler    *CommandHandler
 
 	notificationLine *tview.TextView
+	promptChoices    map[rune]LineCommand
 
 	dbgMoveChan    chan *DebuggerMove
 	breakpointChan chan *nav.UiBreakpoint
+	bpListChan     chan []*api.Breakpoint
+	watchpointChan chan *nav.Watchpoint
 	navState       *nav.Nav
 
 	goroutineChan chan []*api.Goroutine
+	testsChan     chan []string
+
+	outputChan       chan *OutputLine
+	traceChan        chan *TraceHit
+	entryChan        chan *OutputEntry // Results of commands written to the output page.
+	varChan          chan *LoadedVariable
+	outputSearchChan chan string
+	programInput     io.Writer // Input of the target when attached to a pseudo-terminal.
+
+	backend   *Backend
+	onConnect []LineCommand // Commands run whenever a connection to the backend is established.
 }
 
 func parseCommand(input string) LineCommand {
@@ -89,6 +136,9 @@ func (keyHandler *KeyHandler) handleKeyEvent(kp KeyPress) *tcell.EventKey {
 	if view.notificationLine.GetText(true) != "" {
 		if key == tcell.KeyEnter {
 			view.clearNotification()
+		} else if command, ok := view.promptChoices[rune]; ok {
+			view.clearNotification()
+			view.cmdHandler.RunCommand(command)
 		}
 		return nil
 	}
@@ -163,6 +213,25 @@ func (view *View) uiEventLoop() {
 			view.onNewGoroutines(activeGoroutines)
 		case newBp := <-view.breakpointChan:
 			view.onNewBreakpoint(newBp)
+		case bps := <-view.bpListChan:
+			view.onBreakpointsListed(bps)
+		case wp := <-view.watchpointChan:
+			view.onNewWatchpoint(wp)
+		case tests := <-view.testsChan:
+			view.pageView.testsPage.RenderTests(tests)
+		case line := <-view.outputChan:
+			view.pageView.outputPage.AddOutput(line)
+		case hit := <-view.traceChan:
+			view.onTraceHit(hit)
+		case entry := <-view.entryChan:
+			view.pageView.outputPage.AddEntry(entry.Prefix, entry.Text)
+		case loaded := <-view.varChan:
+			view.pageView.varsPage.ReplaceVariable(loaded.Node, loaded.Var)
+		case msg := <-view.backend.Events():
+			view.onBackendLost(msg)
+		case text := <-view.outputSearchChan:
+			view.pageView.outputPage.Search(text)
+			view.pageView.SwitchToPage(IOutputPage)
 		}
 	}
 }
@@ -182,18 +251,25 @@ func (view *View) renderPendingContinue() {
  */
 func (view *View) onDebuggerMove(dbgMove *DebuggerMove) {
 	newState := dbgMove.DbgState
-	line := newState.CurrentThread.Line
-	file := newState.CurrentThread.File
+	file, line := dbgMove.Location()
 	view.navState.DbgState = newState
 	view.navState.CurrentDebuggerPos = nav.DebuggerPos{File: file, Line: line}
 
 	// Navigate to file and update call stack.
 	log.Printf("Debugger move inside file %s on line %d.", file, line-1)
-	view.OpenFile(view.navState.FileCache[file], line-1)
+	if dbgMove.Breakpoints != nil {
+		view.pageView.outputPage.AddMarker(fmt.Sprintf("stopped at %s:%d", file, line))
+	}
+	if view.navState.FileCache[file] != nil {
+		view.OpenFile(view.navState.FileCache[file], line-1)
+	}
 
 	if len(dbgMove.Stack) > 0 {
 		view.navState.CurrentStack = dbgMove.Stack
 		view.navState.CurrentStackFrame = &dbgMove.Stack[0]
+		if dbgMove.Panic != nil && dbgMove.Panic.Cause >= 0 {
+			view.navState.CurrentStackFrame = &dbgMove.Stack[dbgMove.Panic.Cause]
+		}
 	}
 
 	// If hit breakpoint.
@@ -201,9 +277,22 @@ func (view *View) onDebuggerMove(dbgMove *DebuggerMove) {
 
 		log.Printf("Hit breakpoint in %s on line %d.", file, line)
 
-		// Update breakpoint that was hit
-		view.navState.Breakpoints[file][line] = &nav.UiBreakpoint{false, newState.CurrentThread.Breakpoint}
 		view.pageView.RenderBreakpointHit(dbgMove.DbgState.CurrentThread.BreakpointInfo)
+
+		// Switching goroutines doesn't hit the breakpoint again.
+		if bp, ok := view.navState.Breakpoints[file][line]; ok && len(bp.Actions) > 0 && dbgMove.Breakpoints != nil {
+			view.runActions(bp)
+		}
+	}
+	view.navState.UpdateBreakpoints(dbgMove.Breakpoints)
+	if dbgMove.Breakpoints != nil {
+		view.clearTemporaryBreakpoints()
+	}
+
+	// The backend clears watchpoints on the stack once their frame returns.
+	for _, wp := range newState.WatchOutOfScope {
+		delete(view.navState.Watchpoints, wp.ID)
+		view.showNotification(fmt.Sprintf("Watchpoint on %s went out of scope and was removed.", wp.WatchExpr), false)
 	}
 
 	// Update pages.
@@ -212,7 +301,47 @@ func (view *View) onDebuggerMove(dbgMove *DebuggerMove) {
 		view.navState.CurrentStack,
 		view.navState.CurrentStackFrame,
 		view.navState.DbgState.CurrentThread.ReturnValues)
-	view.pageView.RenderJumpToLine(line - 1)
+	if view.navState.FileCache[file] != nil {
+		view.pageView.RenderJumpToLine(line - 1)
+	}
+
+	if dbgMove.Panic != nil {
+		view.pageView.outputPage.AddMarker(fmt.Sprintf("%s: %s", dbgMove.Panic.Catchpoint.Title, dbgMove.Panic.Value))
+		view.pageView.panicPage.RenderPanic(dbgMove.Panic)
+		view.pageView.SwitchToPage(IPanicPage)
+	}
+}
+
+// Temporary breakpoints are removed once the program stops, whether they were
+// hit or not.
+func (view *View) clearTemporaryBreakpoints() {
+	for _, bp := range view.navState.GetAllBreakpoints() {
+		if !bp.Temporary || bp.ID < 0 {
+			continue
+		}
+		if bp.Disabled {
+			view.cmdHandler.RunCommand(&ClearBreakpoint{bp, false, bp})
+		} else {
+			view.cmdHandler.RunCommand(&ClearBreakpoint{bp, false, nil})
+		}
+	}
+}
+
+// Run the commands attached to a breakpoint that was hit, one after another.
+func (view *View) runActions(bp *nav.UiBreakpoint) {
+	cmds := []LineCommand{}
+	for _, action := range bp.Actions {
+		if cmd := parseCommand(action); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	view.cmdHandler.RunCommand(&Sequence{Commands: cmds})
+}
+
+func (view *View) notifyFileNotFound(path string) {
+	view.showNotification(fmt.Sprintf(
+		"Source file %s not found. If the program was built elsewhere, map its source paths with substitutepath.", path,
+	), true)
 }
 
 func (view *View) onNewFile(newFile *nav.File) {
@@ -242,12 +371,77 @@ func (view *View) onNewBreakpoint(newBp *nav.UiBreakpoint) {
 	view.pageView.RefreshCodePage()
 }
 
+func (view *View) onTraceHit(hit *TraceHit) {
+	msg := ""
+	if bp, ok := view.navState.Breakpoints[hit.Breakpoint.File][hit.Breakpoint.Line]; ok {
+		msg = bp.LogMessage
+	}
+	view.pageView.traceLogPage.AddEntry(
+		fmt.Sprintf("%s:%d g%d", hit.Breakpoint.FunctionName, hit.Breakpoint.Line, hit.GoroutineID),
+		formatLogMessage(msg, hit.Info),
+	)
+}
+
+// Add breakpoints listed by the backend. Known ones keep the settings that only
+// exist in the client, such as log messages and actions.
+func (view *View) onBreakpointsListed(bps []*api.Breakpoint) {
+	catchpoints := make(map[string]*api.Breakpoint)
+	for _, bp := range bps {
+		if findCatchpoint(bp.Name) != nil {
+			catchpoints[bp.Name] = bp
+		}
+	}
+	view.navState.Catchpoints = catchpoints
+	view.navState.UpdateBreakpoints(bps)
+	for _, bp := range bps {
+		if _, ok := catchpoints[bp.Name]; ok {
+			continue
+		}
+		if bp.WatchExpr != "" {
+			// Where the expression of a watchpoint set by someone else was evaluated isn't known.
+			if _, ok := view.navState.Watchpoints[bp.ID]; !ok {
+				view.navState.Watchpoints[bp.ID] = &nav.Watchpoint{GoroutineID: -1, Breakpoint: bp}
+			}
+			continue
+		}
+		if _, ok := view.navState.Breakpoints[bp.File][bp.Line]; !ok {
+			view.onNewBreakpoint(&nav.UiBreakpoint{Disabled: false, Breakpoint: bp})
+		}
+	}
+	view.pageView.RenderBreakpoints(view.navState.GetAllBreakpoints())
+	view.pageView.RefreshCodePage()
+}
+
+func (view *View) onNewWatchpoint(wp *nav.Watchpoint) {
+	if wp.Removed {
+		delete(view.navState.Watchpoints, wp.ID)
+	} else {
+		view.navState.Watchpoints[wp.ID] = wp
+	}
+	view.pageView.RenderBreakpoints(view.navState.GetAllBreakpoints())
+}
+
+// Offer to reconnect or relaunch when the backend has failed.
+func (view *View) onBackendLost(msg string) {
+	view.cmdHandler.SetClient(nil)
+	view.indicatorText.SetText(fmt.Sprintf("%s ", gConfig.Icons.IndExitError))
+	choices := map[rune]LineCommand{'c': &Connect{}}
+	msg += "\n(c)onnect again"
+	if !view.backend.IsRemote() {
+		choices['r'] = &Connect{Launch: true}
+		msg += " or (r)elaunch dlv"
+	}
+	view.promptChoices = choices
+	view.showNotification(msg, true)
+}
+
 func (view *View) onNewGoroutines(activeGoroutines []*api.Goroutine) {
 	view.navState.Goroutines = activeGoroutines
-	view.pageView.goroutinePage.RenderGoroutines(
-		activeGoroutines,
-		view.navState.DbgState.CurrentThread.GoroutineID,
-	)
+	currId := -1
+	if view.navState.DbgState != nil && view.navState.DbgState.CurrentThread != nil {
+		currId = view.navState.DbgState.CurrentThread.GoroutineID
+	}
+	view.pageView.goroutinePage.RenderGoroutines(activeGoroutines, currId)
 }
 
 func (view *View) toNormalMode() {
@@ -267,7 +461,47 @@ func (view *View) toCmdMode() {
 	view.currentMode = Cmd
 }
 
+// Enter command mode with text already typed, e.g. to edit an existing value.
+func (view *View) toCmdModeWithText(text string) {
+	view.toCmdMode()
+	view.cmdLine.SetText(text)
+}
+
+// Breakpoint selected in the breakpoints page if it's open, otherwise the one
+// on the current line of the code page.
+func (view *View) selectedBreakpoint() *nav.UiBreakpoint {
+	if view.pageView.CurrentPage() == view.pageView.breakpointsPage {
+		return view.pageView.breakpointsPage.SelectedBreakpoint()
+	}
+	if view.navState.CurrentFile == nil {
+		return nil
+	}
+	// Using 1 based indices on the backend.
+	bp, ok := view.navState.Breakpoints[view.navState.CurrentFile.Path][view.navState.CurrentLine()+1]
+	if !ok || bp.ID < 0 {
+		return nil
+	}
+	return bp
+}
+
+// Scope of the selected stack frame of the current goroutine, along with its
+// function.
+func (view *View) frameScope() (api.EvalScope, *api.Function) {
+	state := view.navState.DbgState
+	scope := api.EvalScope{GoroutineID: state.CurrentThread.GoroutineID}
+	function := state.CurrentThread.Function
+	for i, sf := range view.navState.CurrentStack {
+		if view.navState.CurrentStackFrame != nil && sf.PC == view.navState.CurrentStackFrame.PC {
+			scope.Frame = i
+			function = sf.Function
+			break
+		}
+	}
+	return scope, function
+}
+
 func (view *View) clearNotification() {
+	view.promptChoices = nil
 	view.notificationLine.SetText("")
 	view.masterView.ResizeItem(view.notificationLine, 0, 0)
 	view.masterView.ResizeItem(view.pageView.GetWidget(), 0, 1)
@@ -280,6 +514,7 @@ func (view *View) clearNotification() {
 func (view *View) notifyProgramEnded(exitCode int) {
 	msg := fmt.Sprintf("Program has finished with exit status %d.", exitCode)
 	log.Print(msg)
+	view.pageView.outputPage.AddMarker(fmt.Sprintf("exited with status %d", exitCode))
 	view.showNotification(msg, false)
 	if exitCode == 0 {
 		view.indicatorText.SetText(fmt.Sprintf("%s ", gConfig.Icons.IndExitSuccess))
@@ -288,9 +523,19 @@ func (view *View) notifyProgramEnded(exitCode int) {
 	}
 }
 
+// Show a notification that runs one of the given commands when its key is pressed.
+func (view *View) showPrompt(msg string, choices map[rune]LineCommand) {
+	view.promptChoices = choices
+	view.showNotification(msg, false)
+}
+
 func (view *View) showNotification(msg string, error bool) {
 	msgLen := len(msg)
-	msg += fmt.Sprintf("\n[%s::b]Press Enter to continue", iToColorS(gConfig.Colors.NotifPromptFg))
+	prompt := "Press Enter to continue"
+	if view.promptChoices != nil {
+		prompt = "Press Enter to cancel"
+	}
+	msg += fmt.Sprintf("\n[%s::b]%s", iToColorS(gConfig.Colors.NotifPromptFg), prompt)
 	if error {
 		msgLen += 7
 		view.notificationLine.SetText(fmt.Sprintf("[%s::b]Error[%s:-:-]: %s",
@@ -309,21 +554,30 @@ func (view *View) showNotification(msg string, error bool) {
 	view.pageView.ResizeCodePage(linesText - lines - 1)
 }
 
-func CreateTui(app *tview.Application, navState *nav.Nav, rpcClient *rpc2.RPCClient) View {
+func CreateTui(app *tview.Application, navState *nav.Nav, backend *Backend, outputChan chan *OutputLine) *View {
 
 	var view = View{
-		nwBlocking:     false,
-		commandChan:    make(chan string, 1024),
-		fileChan:       make(chan *nav.File, 1024),
-		dbgMoveChan:    make(chan *DebuggerMove, 1024),
-		goroutineChan:  make(chan []*api.Goroutine, 1024),
-		breakpointChan: make(chan *nav.UiBreakpoint, 1024),
-		navState:       navState,
-		currentMode:    Normal,
-		pageView:       nil,
-	}
-
-	view.cmdHandler = NewCommandHandler(&view, app, rpcClient)
+		nwBlocking:       false,
+		commandChan:      make(chan string, 1024),
+		fileChan:         make(chan *nav.File, 1024),
+		dbgMoveChan:      make(chan *DebuggerMove, 1024),
+		goroutineChan:    make(chan []*api.Goroutine, 1024),
+		testsChan:        make(chan []string, 1024),
+		outputChan:       outputChan,
+		traceChan:        make(chan *TraceHit, 1024),
+		outputSearchChan: make(chan string, 1024),
+		breakpointChan:   make(chan *nav.UiBreakpoint, 1024),
+		bpListChan:       make(chan []*api.Breakpoint, 16),
+		entryChan:        make(chan *OutputEntry, 1024),
+		varChan:          make(chan *LoadedVariable, 16),
+		watchpointChan:   make(chan *nav.Watchpoint, 1024),
+		navState:         navState,
+		currentMode:      Normal,
+		pageView:         nil,
+		backend:          backend,
+	}
+
+	view.cmdHandler = NewCommandHandler(&view, app, nil)
 	view.pageView = NewPageView(view.cmdHandler, navState, app)
 	view.keyHandler = KeyHandler{app: app, view: &view}
 
@@ -380,10 +634,6 @@ func CreateTui(app *tview.Application, navState *nav.Nav, rpcClient *rpc2.RPCCli
 	view.notificationLine = notificationLine
 
 	go view.uiEventLoop()
-	go func() {
-		rpcClient.SetReturnValuesLoadConfig(&defaultConfig)
-	}()
-	go view.cmdHandler.RunCommand(&GetBreakpoints{})
 
-	return view
+	return &view
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/go-delve/delve/service/api"
@@ -120,8 +121,11 @@ func getVarTitle(vr *api.Variable, expanded bool) string {
 		valstr += fmt.Sprintf(" %s", vr.Value)
 	}
 	suffix := ""
+	if text := truncatedText(vr); text != "" {
+		suffix += fmt.Sprintf(" [%s](%s, %s to load more)", iToColorS(gConfig.Colors.VarAddrFg), text, gConfig.Keys.LoadMore)
+	}
 	if vr.Children != nil && len(vr.Children) > 0 {
-		suffix = fmt.Sprintf(" [%s]", iToColorS(gConfig.Colors.ListExpand))
+		suffix += fmt.Sprintf(" [%s]", iToColorS(gConfig.Colors.ListExpand))
 		if expanded {
 			suffix += "-"
 		} else {
@@ -131,15 +135,39 @@ func getVarTitle(vr *api.Variable, expanded bool) string {
 	return namestr + typestr + valstr + suffix + addrstr
 }
 
+// How much of a variable was loaded if it was cut by the load limits, empty if
+// all of it was.
+func truncatedText(vr *api.Variable) string {
+	if vr.OnlyAddr {
+		return "not loaded"
+	}
+	switch vr.Kind {
+	case reflect.String:
+		if int64(len(vr.Value)) < vr.Len {
+			return fmt.Sprintf("%d of %d bytes", len(vr.Value), vr.Len)
+		}
+	case reflect.Array, reflect.Slice, reflect.Struct:
+		if int64(len(vr.Children)) < vr.Len {
+			return fmt.Sprintf("%d of %d", len(vr.Children), vr.Len)
+		}
+	case reflect.Map:
+		// Keys and values of maps are both children.
+		if int64(len(vr.Children)/2) < vr.Len {
+			return fmt.Sprintf("%d of %d", len(vr.Children)/2, vr.Len)
+		}
+	}
+	return ""
+}
+
 func (page *VarsPage) AddVars(parent *tview.TreeNode, vars []api.Variable) {
 
 	addedLocals := 0
 	addedArgs := 0
 	for _, vr := range vars {
-		newNode := tview.NewTreeNode(getVarTitle(&vr, page.expandedCache[vr.Addr])).
-			SetReference(vr)
+		newNode := tview.NewTreeNode("")
 		newNode.SetSelectable(true)
 		newNode.SetColor(tcell.ColorBlack)
+		page.setVariable(newNode, vr)
 
 		if vr.Addr == page.lastSelected.val.Addr {
 			page.treeView.SetCurrentNode(newNode)
@@ -150,31 +178,89 @@ func (page *VarsPage) AddVars(parent *tview.TreeNode, vars []api.Variable) {
 		} else if parent == page.args {
 			addedArgs++
 		}
+		parent.AddChild(newNode)
+	}
+}
+
+func (page *VarsPage) setVariable(node *tview.TreeNode, vr api.Variable) {
+	node.SetText(getVarTitle(&vr, page.expandedCache[vr.Addr])).
+		SetReference(vr)
+
+	// If node has children, initially collapse. Expand on select.
+	if vr.Children != nil && len(vr.Children) > 0 {
+		page.AddVars(node, vr.Children)
 
-		// If node has children, initially collapse. Expand on select.
-		if vr.Children != nil && len(vr.Children) > 0 {
-			page.AddVars(newNode, vr.Children)
+		// Expand or collapse node according to what was cached from previous action.
+		if !page.expandedCache[vr.Addr] {
+			node.CollapseAll()
+		} else {
+			node.Expand()
+		}
 
-			// Expand or collapse node according to what was cached from previous action.
-			if !page.expandedCache[vr.Addr] {
-				newNode.CollapseAll()
+		node.SetSelectedFunc(func() {
+			r := node.GetReference().(api.Variable)
+			page.expandedCache[r.Addr] = !node.IsExpanded()
+			if !node.IsExpanded() {
+				node.Expand()
 			} else {
-				newNode.Expand()
+				node.Collapse()
 			}
+			node.SetText(getVarTitle(&r, page.expandedCache[r.Addr]))
+		})
+	}
+}
+
+// Show a variable that was loaded again in place of the node it was loaded
+// for, expanded.
+func (page *VarsPage) ReplaceVariable(node *tview.TreeNode, vr *api.Variable) {
+	node.ClearChildren()
+	page.expandedCache[vr.Addr] = true
+	page.setVariable(node, *vr)
+}
 
-			newNode.SetSelectedFunc(func() {
-				r := newNode.GetReference().(api.Variable)
-				page.expandedCache[r.Addr] = !newNode.IsExpanded()
-				if !newNode.IsExpanded() {
-					newNode.Expand()
-				} else {
-					newNode.Collapse()
+// Expression evaluating to the selected variable, such as "req.Header.Host"
+// for a field of a local variable.
+func (page *VarsPage) selectedExpression() string {
+	selected := page.treeView.GetCurrentNode()
+	if selected == nil || selected.GetReference() == nil {
+		return ""
+	}
+	parents := make(map[*tview.TreeNode]*tview.TreeNode)
+	page.treeView.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
+		parents[node] = parent
+		return true
+	})
+
+	path := []*tview.TreeNode{}
+	for node := selected; node != nil && node.GetReference() != nil; node = parents[node] {
+		path = append([]*tview.TreeNode{node}, path...)
+	}
+
+	expr := ""
+	for i, node := range path {
+		vr := node.GetReference().(api.Variable)
+		if i == 0 {
+			expr = vr.Name
+			continue
+		}
+		switch parent := path[i-1].GetReference().(api.Variable); parent.Kind {
+		case reflect.Struct:
+			expr += "." + vr.Name
+		case reflect.Ptr:
+			expr = "(*" + expr + ")"
+		case reflect.Array, reflect.Slice:
+			for idx, sibling := range path[i-1].GetChildren() {
+				if sibling == node {
+					expr += fmt.Sprintf("[%d]", idx)
 				}
-				newNode.SetText(getVarTitle(&r, page.expandedCache[r.Addr]))
-			})
+			}
+		default:
+			// Elements of maps and interfaces can't be named, refer to the memory directly.
+			selectedVar := selected.GetReference().(api.Variable)
+			return fmt.Sprintf("*(*%s)(%#x)", selectedVar.Type, selectedVar.Addr)
 		}
-		parent.AddChild(newNode)
 	}
+	return expr
 }
 
 func (varsView *VarsPage) GetName() string {
@@ -182,6 +268,23 @@ func (varsView *VarsPage) GetName() string {
 }
 
 func (page *VarsPage) HandleKeyEvent(event *tcell.EventKey) *tcell.EventKey {
+	if keyPressed(event, gConfig.Keys.Watch) {
+		if expr := page.selectedExpression(); expr != "" {
+			page.commandHandler.RunCommand(&CreateWatchpoint{Expr: expr, Type: api.WatchWrite})
+		}
+		return nil
+	}
+	if keyPressed(event, gConfig.Keys.LoadMore) {
+		selected := page.treeView.GetCurrentNode()
+		if expr := page.selectedExpression(); expr != "" {
+			page.commandHandler.RunCommand(&LoadMore{
+				Expr: expr,
+				Var:  selected.GetReference().(api.Variable),
+				Node: selected,
+			})
+		}
+		return nil
+	}
 
 	page.treeView.InputHandler()(event, func(p tview.Primitive) {})
 	if page.treeView.GetCurrentNode() != nil && page.treeView.GetCurrentNode().GetReference() != nil {
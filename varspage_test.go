@@ -0,0 +1,35 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-delve/delve/service/api"
+)
+
+func TestTruncatedText(t *testing.T) {
+	children := func(n int) []api.Variable {
+		return make([]api.Variable, n)
+	}
+	tests := []struct {
+		name string
+		vr   api.Variable
+		want string
+	}{
+		{"unloaded", api.Variable{OnlyAddr: true, Kind: reflect.Struct}, "not loaded"},
+		{"string", api.Variable{Kind: reflect.String, Value: "abc", Len: 10}, "3 of 10 bytes"},
+		{"whole string", api.Variable{Kind: reflect.String, Value: "abc", Len: 3}, ""},
+		{"slice", api.Variable{Kind: reflect.Slice, Children: children(2), Len: 5}, "2 of 5"},
+		{"whole slice", api.Variable{Kind: reflect.Slice, Children: children(5), Len: 5}, ""},
+		{"array", api.Variable{Kind: reflect.Array, Children: children(1), Len: 4}, "1 of 4"},
+		{"struct", api.Variable{Kind: reflect.Struct, Children: children(3), Len: 6}, "3 of 6"},
+		{"map", api.Variable{Kind: reflect.Map, Children: children(4), Len: 3}, "2 of 3"},
+		{"whole map", api.Variable{Kind: reflect.Map, Children: children(6), Len: 3}, ""},
+		{"int", api.Variable{Kind: reflect.Int, Value: "1"}, ""},
+	}
+	for _, tt := range tests {
+		if got := truncatedText(&tt.vr); got != tt.want {
+			t.Errorf("truncatedText(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}